@@ -1,14 +1,18 @@
 package ucodesdk
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"net/http"
+	neturl "net/url"
 )
 
 func (u *object) Auth() AuthI {
 	return &APIAuth{
-		config: u.config,
+		sdk: u,
 	}
 }
 
@@ -39,13 +43,99 @@ type AuthI interface {
 	*/
 	ResetPassword(data map[string]any) *ResetPassword
 	Login(body map[string]any) *Login
+	/*
+		LoginWith logs in with a typed strategy, validated before sending and
+		routed to /v2/login or /v2/login/with-option.
+
+		Works for [Mongo, Postgres]
+
+		sdk.Auth().
+			LoginWith(ucodesdk.PhoneOTPLogin{Phone: phone, SmsId: code.Data.SmsId, Otp: otp}).
+			Exec()
+	*/
+	LoginWith(strategy LoginStrategy) *LoginWith
 	SendCode(data map[string]any) *SendCode
+	/*
+		RefreshToken exchanges a refresh token for a new access token.
+
+		Works for [Mongo, Postgres]
+
+		sdk.Auth().
+			RefreshToken(loginResponse.Data.Token.RefreshToken).
+			Exec()
+
+		TokenSource calls it automatically before the access token expires.
+	*/
+	RefreshToken(refreshToken string) *RefreshToken
+	/*
+		Logout revokes the session of a refresh token.
+
+		Works for [Mongo, Postgres]
+
+		sdk.Auth().
+			Logout(ucodesdk.LogoutRequest{RefreshToken: token.RefreshToken}).
+			Exec()
+	*/
+	Logout(request LogoutRequest) *Logout
+	/*
+		ChangePassword changes the password of an authenticated user, who has
+		to confirm the old one.
+
+		Works for [Mongo, Postgres]
+
+		ucodesdk.AsUser(sdk, login).Auth().
+			ChangePassword(ucodesdk.ChangePasswordRequest{UserId: id, OldPassword: old, NewPassword: new}).
+			Exec()
+	*/
+	ChangePassword(request ChangePasswordRequest) *ChangePassword
+	/*
+		VerifyOTP checks the code the user received after SendCode.
+
+		Works for [Mongo, Postgres]
+
+		sdk.Auth().
+			VerifyOTP(ucodesdk.VerifyOTPRequest{SmsId: code.Data.SmsId, Otp: "123456"}).
+			Exec()
+	*/
+	VerifyOTP(request VerifyOTPRequest) *VerifyOTP
+	/*
+		Sessions lists the active sessions of a user.
+
+		Works for [Mongo, Postgres]
+
+		sdk.Auth().
+			Sessions(ucodesdk.ListSessionsRequest{UserId: id}).
+			Exec()
+	*/
+	Sessions(request ListSessionsRequest) *ListSessions
+	/*
+		DeleteSession revokes a session, signing the user out of the device
+		it belongs to.
+
+		Works for [Mongo, Postgres]
+
+		sdk.Auth().
+			DeleteSession(session.Id).
+			Exec()
+	*/
+	DeleteSession(id string) *DeleteSession
+	/*
+		HasAccess validates an access token with the auth service and returns
+		the user it belongs to, with their current role and permissions.
+
+		Works for [Mongo, Postgres]
+
+		sdk.Auth().
+			HasAccess(accessToken).
+			Exec()
+	*/
+	HasAccess(accessToken string) *HasAccess
 }
 
 func (a *APIAuth) Register(data map[string]any) *Register {
 	return &Register{
-		config: a.config,
-		data:   AuthRequest{Body: data},
+		sdk:  a.sdk,
+		data: AuthRequest{Body: data},
 	}
 }
 
@@ -55,15 +145,25 @@ func (a *Register) Headers(headers map[string]string) *Register {
 }
 
 func (a *Register) Exec() (RegisterResponse, Response, error) {
+	return a.ExecContext(context.Background())
+}
+
+func (a *Register) ExecContext(ctx context.Context) (RegisterResponse, Response, error) {
 	var (
 		response = Response{
 			Status: "done",
 		}
 		registerObject RegisterResponse
-		url            = fmt.Sprintf("%s/v2/register?project-id=%s", a.config.BaseAuthUrl, a.config.ProjectId)
+		url            = fmt.Sprintf("%s/v2/register?project-id=%s", a.sdk.config.BaseAuthUrl, a.sdk.config.ProjectId)
 	)
 
-	registerResponseInByte, err := DoRequest(url, http.MethodPost, a.data.Body, a.data.Headers)
+	registerResponseInByte, err := a.sdk.send(ctx, apiCall{
+		operation: OpAuthRegister,
+		url:       url,
+		method:    http.MethodPost,
+		body:      a.data.Body,
+		headers:   a.data.Headers,
+	})
 	if err != nil {
 		response.Data = map[string]any{"description": string(registerResponseInByte), "message": "Can't send request", "error": err.Error()}
 		response.Status = "error"
@@ -82,8 +182,8 @@ func (a *Register) Exec() (RegisterResponse, Response, error) {
 
 func (a *APIAuth) ResetPassword(data map[string]any) *ResetPassword {
 	return &ResetPassword{
-		config: a.config,
-		data:   AuthRequest{Body: data},
+		sdk:  a.sdk,
+		data: AuthRequest{Body: data},
 	}
 }
 
@@ -93,19 +193,23 @@ func (a *ResetPassword) Headers(headers map[string]string) *ResetPassword {
 }
 
 func (a *ResetPassword) Exec() (Response, error) {
+	return a.ExecContext(context.Background())
+}
+
+func (a *ResetPassword) ExecContext(ctx context.Context) (Response, error) {
 	var (
 		response = Response{Status: "done"}
-		url      = fmt.Sprintf("%s/v2/reset-password", a.config.BaseAuthUrl)
+		url      = fmt.Sprintf("%s/v2/reset-password", a.sdk.config.BaseAuthUrl)
 	)
 
-	var appId = a.config.AppId
-
-	header := map[string]string{
-		"authorization": "API-KEY",
-		"X-API-KEY":     appId,
-	}
-
-	_, err := DoRequest(url, http.MethodPut, a.data.Body, header)
+	_, err := a.sdk.send(ctx, apiCall{
+		operation:  OpAuthResetPassword,
+		url:        url,
+		method:     http.MethodPut,
+		body:       a.data.Body,
+		headers:    a.data.Headers,
+		authorized: true,
+	})
 	if err != nil {
 		response.Data = map[string]any{"message": "Error while reset password", "error": err.Error()}
 		response.Status = "error"
@@ -117,8 +221,8 @@ func (a *ResetPassword) Exec() (Response, error) {
 
 func (a *APIAuth) Login(data map[string]any) *Login {
 	return &Login{
-		config: a.config,
-		data:   AuthRequest{Body: data},
+		sdk:  a.sdk,
+		data: AuthRequest{Body: data},
 	}
 }
 
@@ -128,17 +232,27 @@ func (a *Login) Headers(headers map[string]string) *Login {
 }
 
 func (a *Login) Exec() (LoginResponse, Response, error) {
+	return a.ExecContext(context.Background())
+}
+
+func (a *Login) ExecContext(ctx context.Context) (LoginResponse, Response, error) {
 	var (
 		response    = Response{Status: "done"}
 		loginObject LoginResponse
-		url         = fmt.Sprintf("%s/v2/login", a.config.BaseAuthUrl)
+		url         = fmt.Sprintf("%s/v2/login", a.sdk.config.BaseAuthUrl)
 	)
 
 	if a.data.Body["project_id"] == nil {
-		a.data.Body["project_id"] = a.config.ProjectId
+		a.data.Body["project_id"] = a.sdk.config.ProjectId
 	}
 
-	loginResponseInByte, err := DoRequest(url, http.MethodPost, a.data.Body, a.data.Headers)
+	loginResponseInByte, err := a.sdk.send(ctx, apiCall{
+		operation: OpAuthLogin,
+		url:       url,
+		method:    http.MethodPost,
+		body:      a.data.Body,
+		headers:   a.data.Headers,
+	})
 	if err != nil {
 		response.Data = map[string]any{"description": string(loginResponseInByte), "message": "Can't send request", "error": err.Error()}
 		response.Status = "error"
@@ -156,13 +270,23 @@ func (a *Login) Exec() (LoginResponse, Response, error) {
 }
 
 func (a *Login) ExecWithOption() (LoginWithOptionResponse, Response, error) {
+	return a.ExecWithOptionContext(context.Background())
+}
+
+func (a *Login) ExecWithOptionContext(ctx context.Context) (LoginWithOptionResponse, Response, error) {
 	var (
 		response    = Response{Status: "done"}
 		loginObject LoginWithOptionResponse
-		url         = fmt.Sprintf("%s/v2/login/with-option?project-id=%s", a.config.BaseAuthUrl, a.config.ProjectId)
+		url         = fmt.Sprintf("%s/v2/login/with-option?project-id=%s", a.sdk.config.BaseAuthUrl, a.sdk.config.ProjectId)
 	)
 
-	loginResponseInByte, err := DoRequest(url, http.MethodPost, a.data.Body, a.data.Headers)
+	loginResponseInByte, err := a.sdk.send(ctx, apiCall{
+		operation: OpAuthLoginWithOption,
+		url:       url,
+		method:    http.MethodPost,
+		body:      a.data.Body,
+		headers:   a.data.Headers,
+	})
 	if err != nil {
 		response.Data = map[string]any{"description": string(loginResponseInByte), "message": "Can't send request", "error": err.Error()}
 		response.Status = "error"
@@ -181,8 +305,8 @@ func (a *Login) ExecWithOption() (LoginWithOptionResponse, Response, error) {
 
 func (a *APIAuth) SendCode(data map[string]any) *SendCode {
 	return &SendCode{
-		config: a.config,
-		data:   AuthRequest{Body: data},
+		sdk:  a.sdk,
+		data: AuthRequest{Body: data},
 	}
 }
 
@@ -192,13 +316,23 @@ func (a *SendCode) Headers(headers map[string]string) *SendCode {
 }
 
 func (a *SendCode) Exec() (SendCodeResponse, Response, error) {
+	return a.ExecContext(context.Background())
+}
+
+func (a *SendCode) ExecContext(ctx context.Context) (SendCodeResponse, Response, error) {
 	var (
 		response   = Response{Status: "done"}
 		codeObject SendCodeResponse
-		url        = fmt.Sprintf("%s/v2/send-code", a.config.BaseAuthUrl)
+		url        = fmt.Sprintf("%s/v2/send-code", a.sdk.config.BaseAuthUrl)
 	)
 
-	codeResponseInByte, err := DoRequest(url, http.MethodPost, a.data.Body, a.data.Headers)
+	codeResponseInByte, err := a.sdk.send(ctx, apiCall{
+		operation: OpAuthSendCode,
+		url:       url,
+		method:    http.MethodPost,
+		body:      a.data.Body,
+		headers:   a.data.Headers,
+	})
 	if err != nil {
 		response.Data = map[string]any{"description": string(codeResponseInByte), "message": "Can't send request", "error": err.Error()}
 		response.Status = "error"
@@ -214,3 +348,357 @@ func (a *SendCode) Exec() (SendCodeResponse, Response, error) {
 
 	return codeObject, response, nil
 }
+
+func (a *APIAuth) RefreshToken(refreshToken string) *RefreshToken {
+	return &RefreshToken{
+		sdk:          a.sdk,
+		refreshToken: refreshToken,
+	}
+}
+
+func (a *RefreshToken) Headers(headers map[string]string) *RefreshToken {
+	a.headers = headers
+	return a
+}
+
+func (a *RefreshToken) Exec() (RefreshTokenResponse, Response, error) {
+	return a.ExecContext(context.Background())
+}
+
+func (a *RefreshToken) ExecContext(ctx context.Context) (RefreshTokenResponse, Response, error) {
+	var (
+		response      = Response{Status: "done"}
+		refreshObject RefreshTokenResponse
+		url           = fmt.Sprintf("%s/v2/refresh?project-id=%s", a.sdk.config.BaseAuthUrl, a.sdk.config.ProjectId)
+	)
+
+	refreshResponseInByte, err := a.sdk.send(ctx, apiCall{
+		operation: OpAuthRefreshToken,
+		url:       url,
+		method:    http.MethodPut,
+		body:      map[string]any{"refresh_token": a.refreshToken},
+		headers:   a.headers,
+	})
+	if err != nil {
+		response.Data = map[string]any{"description": string(refreshResponseInByte), "message": "Can't send request", "error": err.Error()}
+		response.Status = "error"
+		return RefreshTokenResponse{}, response, err
+	}
+
+	err = json.Unmarshal(refreshResponseInByte, &refreshObject)
+	if err != nil {
+		response.Data = map[string]any{"description": string(refreshResponseInByte), "message": "Error while unmarshalling refresh token object", "error": err.Error()}
+		response.Status = "error"
+		return RefreshTokenResponse{}, response, err
+	}
+
+	return refreshObject, response, nil
+}
+
+func (a *APIAuth) Logout(request LogoutRequest) *Logout {
+	return &Logout{
+		sdk:     a.sdk,
+		request: request,
+	}
+}
+
+func (a *Logout) Headers(headers map[string]string) *Logout {
+	a.headers = headers
+	return a
+}
+
+func (a *Logout) Exec() (Response, error) {
+	return a.ExecContext(context.Background())
+}
+
+func (a *Logout) ExecContext(ctx context.Context) (Response, error) {
+	var (
+		response = Response{Status: "done"}
+		url      = fmt.Sprintf("%s/v2/logout", a.sdk.config.BaseAuthUrl)
+	)
+
+	if a.request.RefreshToken == "" {
+		response.Data = map[string]any{"message": "Error while logout", "error": "refresh token is empty"}
+		response.Status = "error"
+		return response, fmt.Errorf("refresh token is empty")
+	}
+
+	_, err := a.sdk.send(ctx, apiCall{
+		operation:  OpAuthLogout,
+		url:        url,
+		method:     http.MethodPost,
+		body:       a.request,
+		headers:    a.headers,
+		authorized: true,
+	})
+	if err != nil {
+		response.Data = map[string]any{"message": "Error while logout", "error": err.Error()}
+		response.Status = "error"
+		return response, err
+	}
+
+	return response, nil
+}
+
+func (a *APIAuth) ChangePassword(request ChangePasswordRequest) *ChangePassword {
+	return &ChangePassword{
+		sdk:     a.sdk,
+		request: request,
+	}
+}
+
+func (a *ChangePassword) Headers(headers map[string]string) *ChangePassword {
+	a.headers = headers
+	return a
+}
+
+func (a *ChangePassword) Exec() (Response, error) {
+	return a.ExecContext(context.Background())
+}
+
+func (a *ChangePassword) ExecContext(ctx context.Context) (Response, error) {
+	var (
+		response = Response{Status: "done"}
+		url      = fmt.Sprintf("%s/v2/change-password?project-id=%s", a.sdk.config.BaseAuthUrl, a.sdk.config.ProjectId)
+	)
+
+	if a.request.UserId == "" || a.request.OldPassword == "" || a.request.NewPassword == "" {
+		response.Data = map[string]any{"message": "Error while change password", "error": "user id, old password and new password are required"}
+		response.Status = "error"
+		return response, fmt.Errorf("user id, old password and new password are required")
+	}
+
+	_, err := a.sdk.send(ctx, apiCall{
+		operation:  OpAuthChangePassword,
+		url:        url,
+		method:     http.MethodPut,
+		body:       a.request,
+		headers:    a.headers,
+		authorized: true,
+	})
+	if err != nil {
+		response.Data = map[string]any{"message": "Error while change password", "error": err.Error()}
+		response.Status = "error"
+		return response, err
+	}
+
+	return response, nil
+}
+
+func (a *APIAuth) VerifyOTP(request VerifyOTPRequest) *VerifyOTP {
+	return &VerifyOTP{
+		sdk:     a.sdk,
+		request: request,
+	}
+}
+
+func (a *VerifyOTP) Headers(headers map[string]string) *VerifyOTP {
+	a.headers = headers
+	return a
+}
+
+func (a *VerifyOTP) Exec() (VerifyOTPResponse, Response, error) {
+	return a.ExecContext(context.Background())
+}
+
+func (a *VerifyOTP) ExecContext(ctx context.Context) (VerifyOTPResponse, Response, error) {
+	var (
+		response     = Response{Status: "done"}
+		verifyObject VerifyOTPResponse
+		url          = fmt.Sprintf("%s/v2/verify/%s/%s?project-id=%s", a.sdk.config.BaseAuthUrl, neturl.PathEscape(a.request.SmsId), neturl.PathEscape(a.request.Otp), a.sdk.config.ProjectId)
+	)
+
+	if a.request.SmsId == "" || a.request.Otp == "" {
+		response.Data = map[string]any{"message": "Error while verifying otp", "error": "sms id and otp are required"}
+		response.Status = "error"
+		return VerifyOTPResponse{}, response, fmt.Errorf("sms id and otp are required")
+	}
+
+	verifyResponseInByte, err := a.sdk.send(ctx, apiCall{
+		operation: OpAuthVerifyOTP,
+		url:       url,
+		method:    http.MethodPost,
+		body:      a.request,
+		headers:   a.headers,
+	})
+	if err != nil {
+		err = redactOTP(url, err)
+		response.Data = map[string]any{"description": string(verifyResponseInByte), "message": "Can't send request", "error": err.Error()}
+		response.Status = "error"
+		return VerifyOTPResponse{}, response, err
+	}
+
+	err = json.Unmarshal(verifyResponseInByte, &verifyObject)
+	if err != nil {
+		response.Data = map[string]any{"description": string(verifyResponseInByte), "message": "Error while unmarshalling verify otp object", "error": err.Error()}
+		response.Status = "error"
+		return VerifyOTPResponse{}, response, err
+	}
+
+	return verifyObject, response, nil
+}
+
+// redactOTP replaces the code in the URL that err, an error of the VerifyOTP
+// call to url, carries, so that it does not reach the messages callers
+// return and log.
+func redactOTP(url string, err error) error {
+	redactedURL := redactURL(&Call{Operation: OpAuthVerifyOTP, URL: url})
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		apiErr.URL = redactedURL
+	}
+	var urlErr *neturl.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = redactedURL
+	}
+
+	return err
+}
+
+func (a *APIAuth) Sessions(request ListSessionsRequest) *ListSessions {
+	return &ListSessions{
+		sdk:     a.sdk,
+		request: request,
+	}
+}
+
+func (a *ListSessions) Headers(headers map[string]string) *ListSessions {
+	a.headers = headers
+	return a
+}
+
+func (a *ListSessions) Exec() (ListSessionsResponse, Response, error) {
+	return a.ExecContext(context.Background())
+}
+
+func (a *ListSessions) ExecContext(ctx context.Context) (ListSessionsResponse, Response, error) {
+	var (
+		response       = Response{Status: "done"}
+		sessionsObject ListSessionsResponse
+		url            = fmt.Sprintf("%s/v2/sessions?project-id=%s&user-id=%s", a.sdk.config.BaseAuthUrl, a.sdk.config.ProjectId, neturl.QueryEscape(a.request.UserId))
+	)
+
+	if a.request.Limit > 0 {
+		url += fmt.Sprintf("&limit=%d", a.request.Limit)
+	}
+	if a.request.Offset > 0 {
+		url += fmt.Sprintf("&offset=%d", a.request.Offset)
+	}
+
+	sessionsResponseInByte, err := a.sdk.send(ctx, apiCall{
+		operation:  OpAuthListSessions,
+		url:        url,
+		method:     http.MethodGet,
+		headers:    a.headers,
+		authorized: true,
+		idempotent: true,
+	})
+	if err != nil {
+		response.Data = map[string]any{"description": string(sessionsResponseInByte), "message": "Can't send request", "error": err.Error()}
+		response.Status = "error"
+		return ListSessionsResponse{}, response, err
+	}
+
+	err = json.Unmarshal(sessionsResponseInByte, &sessionsObject)
+	if err != nil {
+		response.Data = map[string]any{"description": string(sessionsResponseInByte), "message": "Error while unmarshalling sessions object", "error": err.Error()}
+		response.Status = "error"
+		return ListSessionsResponse{}, response, err
+	}
+
+	return sessionsObject, response, nil
+}
+
+func (a *APIAuth) DeleteSession(id string) *DeleteSession {
+	return &DeleteSession{
+		sdk: a.sdk,
+		id:  id,
+	}
+}
+
+func (a *DeleteSession) Headers(headers map[string]string) *DeleteSession {
+	a.headers = headers
+	return a
+}
+
+func (a *DeleteSession) Exec() (Response, error) {
+	return a.ExecContext(context.Background())
+}
+
+func (a *DeleteSession) ExecContext(ctx context.Context) (Response, error) {
+	var (
+		response = Response{Status: "done"}
+		url      = fmt.Sprintf("%s/v2/sessions/%s", a.sdk.config.BaseAuthUrl, neturl.PathEscape(a.id))
+	)
+
+	_, err := a.sdk.send(ctx, apiCall{
+		operation:  OpAuthDeleteSession,
+		url:        url,
+		method:     http.MethodDelete,
+		body:       Request{Data: map[string]any{}},
+		headers:    a.headers,
+		authorized: true,
+		idempotent: true,
+	})
+	if err != nil {
+		response.Data = map[string]any{"message": "Error while deleting session", "error": err.Error()}
+		response.Status = "error"
+		return response, err
+	}
+
+	return response, nil
+}
+
+func (a *APIAuth) HasAccess(accessToken string) *HasAccess {
+	return &HasAccess{
+		sdk:         a.sdk,
+		accessToken: accessToken,
+	}
+}
+
+func (a *HasAccess) Headers(headers map[string]string) *HasAccess {
+	a.headers = headers
+	return a
+}
+
+func (a *HasAccess) Exec() (LoginResponse, Response, error) {
+	return a.ExecContext(context.Background())
+}
+
+func (a *HasAccess) ExecContext(ctx context.Context) (LoginResponse, Response, error) {
+	var (
+		response     = Response{Status: "done"}
+		accessObject LoginResponse
+		url          = fmt.Sprintf("%s/v2/has-access?project-id=%s", a.sdk.config.BaseAuthUrl, a.sdk.config.ProjectId)
+		headers      = maps.Clone(a.headers)
+	)
+
+	if headers == nil {
+		headers = map[string]string{}
+	}
+	headers["Authorization"] = "Bearer " + a.accessToken
+
+	accessResponseInByte, err := a.sdk.send(ctx, apiCall{
+		operation:  OpAuthHasAccess,
+		url:        url,
+		method:     http.MethodPost,
+		body:       map[string]any{"access_token": a.accessToken},
+		headers:    headers,
+		idempotent: true,
+	})
+	if err != nil {
+		response.Data = map[string]any{"description": string(accessResponseInByte), "message": "Can't send request", "error": err.Error()}
+		response.Status = "error"
+		return LoginResponse{}, response, err
+	}
+
+	err = json.Unmarshal(accessResponseInByte, &accessObject)
+	if err != nil {
+		response.Data = map[string]any{"description": string(accessResponseInByte), "message": "Error while unmarshalling has access object", "error": err.Error()}
+		response.Status = "error"
+		return LoginResponse{}, response, err
+	}
+
+	return accessObject, response, nil
+}
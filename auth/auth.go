@@ -0,0 +1,154 @@
+/*
+Package auth authenticates requests made to services built next to u-code
+functions with the access tokens of u-code users.
+
+A Validator resolves a Bearer token to a User, either locally by verifying
+the JWT (JWTValidator) or by asking the auth service (ServiceValidator).
+HTTPMiddleware and the gRPC interceptors place the User in the request
+context, where FromContext and the helpers read it back:
+
+	validator := &auth.JWTValidator{Key: []byte(secret)}
+	mux.Handle("/orders", auth.HTTPMiddleware(validator)(orders))
+
+	func orders(w http.ResponseWriter, r *http.Request) {
+		user, _ := auth.FromContext(r.Context())
+		...
+	}
+*/
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	ucodesdk "github.com/ucode-io/ucode_sdk"
+)
+
+var (
+	// ErrMissingToken is returned when a request carries no Bearer token.
+	ErrMissingToken = errors.New("auth: missing bearer token")
+	// ErrInvalidToken is returned when a token is malformed, expired or
+	// rejected by the auth service.
+	ErrInvalidToken = errors.New("auth: invalid token")
+)
+
+// User is the identity resolved from an access token, shaped like the data
+// of ucodesdk.LoginResponse.
+type User struct {
+	UserId        string
+	UserIdAuth    string
+	ProjectId     string
+	EnvironmentId string
+	ResourceId    string
+	RoleId        string
+	ClientTypeId  string
+
+	Role           map[string]any
+	ClientType     map[string]any
+	Permissions    []map[string]any
+	AppPermissions []map[string]any
+	UserData       map[string]any
+
+	// Token is the access token the user was resolved from.
+	Token string
+	// Claims are the claims of the token when it was verified locally.
+	Claims map[string]any
+}
+
+// Client returns a client of sdk acting on behalf of u, so that the calls
+// made while serving the request are subject to the user's permissions.
+func (u *User) Client(sdk ucodesdk.UcodeApis) ucodesdk.UcodeApis {
+	return ucodesdk.WithAccessToken(sdk, u.Token, u.ResourceId, u.EnvironmentId)
+}
+
+// Validator resolves an access token to the user it was issued to. Errors
+// should wrap ErrInvalidToken when the token itself is rejected.
+type Validator interface {
+	Validate(ctx context.Context, token string) (*User, error)
+}
+
+// ValidatorFunc adapts a function to a Validator.
+type ValidatorFunc func(ctx context.Context, token string) (*User, error)
+
+func (f ValidatorFunc) Validate(ctx context.Context, token string) (*User, error) {
+	return f(ctx, token)
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying user.
+func NewContext(ctx context.Context, user *User) context.Context {
+	return context.WithValue(ctx, contextKey{}, user)
+}
+
+// FromContext returns the user placed in ctx by the middleware.
+func FromContext(ctx context.Context) (*User, bool) {
+	user, ok := ctx.Value(contextKey{}).(*User)
+	return user, ok && user != nil
+}
+
+// UserId returns the id of the user in ctx, or "" when there is none.
+func UserId(ctx context.Context) string {
+	if user, ok := FromContext(ctx); ok {
+		return user.UserId
+	}
+	return ""
+}
+
+// RoleId returns the role id of the user in ctx, or "" when there is none.
+func RoleId(ctx context.Context) string {
+	if user, ok := FromContext(ctx); ok {
+		return user.RoleId
+	}
+	return ""
+}
+
+// ClientTypeId returns the client type id of the user in ctx, or "" when
+// there is none.
+func ClientTypeId(ctx context.Context) string {
+	if user, ok := FromContext(ctx); ok {
+		return user.ClientTypeId
+	}
+	return ""
+}
+
+// Permissions returns the permissions of the user in ctx.
+func Permissions(ctx context.Context) []map[string]any {
+	if user, ok := FromContext(ctx); ok {
+		return user.Permissions
+	}
+	return nil
+}
+
+// bearerToken extracts the token of an Authorization header value.
+func bearerToken(authorization string) (string, error) {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(authorization), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", ErrMissingToken
+	}
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", ErrMissingToken
+	}
+
+	return token, nil
+}
+
+// authenticate validates the token of an Authorization header value and
+// returns ctx carrying the resolved user.
+func authenticate(ctx context.Context, validator Validator, authorization string) (context.Context, error) {
+	token, err := bearerToken(authorization)
+	if err != nil {
+		return ctx, err
+	}
+
+	user, err := validator.Validate(ctx, token)
+	if err != nil {
+		return ctx, err
+	}
+	user.Token = token
+
+	return NewContext(ctx, user), nil
+}
@@ -0,0 +1,181 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ucodesdk "github.com/ucode-io/ucode_sdk"
+	"github.com/ucode-io/ucode_sdk/ucodetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var secret = []byte("secret")
+
+func signToken(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	require.NoError(t, err)
+
+	return token
+}
+
+func TestJWTValidator(t *testing.T) {
+	ctx := context.Background()
+	validator := &JWTValidator{Key: secret}
+
+	token := signToken(t, jwt.MapClaims{
+		"user_id":        "user-1",
+		"role_id":        "role-1",
+		"client_type_id": "ct-1",
+		"project_id":     "project-1",
+		"permissions":    []any{map[string]any{"table_slug": "orders", "read": "Yes"}},
+		"exp":            time.Now().Add(time.Hour).Unix(),
+	})
+
+	user, err := validator.Validate(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", user.UserId)
+	assert.Equal(t, "role-1", user.RoleId)
+	assert.Equal(t, "ct-1", user.ClientTypeId)
+	assert.Equal(t, []map[string]any{{"table_slug": "orders", "read": "Yes"}}, user.Permissions)
+
+	expired := signToken(t, jwt.MapClaims{"user_id": "user-1", "exp": time.Now().Add(-time.Hour).Unix()})
+	_, err = validator.Validate(ctx, expired)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = (&JWTValidator{Key: []byte("other")}).Validate(ctx, token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = (&JWTValidator{Key: secret, Methods: []string{"RS256"}}).Validate(ctx, token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = (&JWTValidator{Key: secret, Issuer: "ucode"}).Validate(ctx, token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestServiceValidator(t *testing.T) {
+	ctx := context.Background()
+	server := ucodetest.New(t)
+	sdk := server.SDK()
+	userId := server.AddUser("john", "secret", map[string]any{"role_id": "role-1", "client_type_id": "ct-1"})
+
+	login, _, err := sdk.Auth().Login(map[string]any{"username": "john", "password": "secret"}).Exec()
+	require.NoError(t, err)
+
+	validator := &ServiceValidator{SDK: sdk}
+
+	user, err := validator.Validate(ctx, login.Data.Token.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, userId, user.UserId)
+	assert.Equal(t, "role-1", user.RoleId)
+	assert.Equal(t, "ct-1", user.ClientTypeId)
+
+	_, err = validator.Validate(ctx, "unknown")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestServiceValidatorSendsThroughSDK(t *testing.T) {
+	server := ucodetest.New(t)
+	server.AddUser("john", "secret", nil)
+
+	var calls []*ucodesdk.Call
+	cfg := server.Config()
+	cfg.ResourceId = "resource-1"
+	cfg.Middleware = []ucodesdk.Middleware{func(next ucodesdk.RoundTripFunc) ucodesdk.RoundTripFunc {
+		return func(ctx context.Context, call *ucodesdk.Call) (*ucodesdk.Result, error) {
+			calls = append(calls, call)
+			return next(ctx, call)
+		}
+	}}
+	sdk := ucodesdk.New(cfg)
+
+	login, _, err := sdk.Auth().Login(map[string]any{"username": "john", "password": "secret"}).Exec()
+	require.NoError(t, err)
+
+	_, err = (&ServiceValidator{SDK: sdk}).Validate(context.Background(), login.Data.Token.AccessToken)
+	require.NoError(t, err)
+
+	require.Len(t, calls, 2)
+	assert.Equal(t, ucodesdk.OpAuthHasAccess, calls[1].Operation)
+	assert.Equal(t, "resource-1", calls[1].Headers["Resource-Id"])
+	assert.Equal(t, "Bearer "+login.Data.Token.AccessToken, calls[1].Headers["Authorization"])
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	token := signToken(t, jwt.MapClaims{"user_id": "user-1", "role_id": "role-1", "exp": time.Now().Add(time.Hour).Unix()})
+
+	handler := HTTPMiddleware(&JWTValidator{Key: secret})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := FromContext(r.Context())
+		require.True(t, ok)
+		assert.Equal(t, token, user.Token)
+		w.Write([]byte(UserId(r.Context()) + " " + RoleId(r.Context())))
+	}))
+
+	for _, tt := range []struct {
+		name          string
+		authorization string
+		status        int
+	}{
+		{"valid", "Bearer " + token, http.StatusOK},
+		{"missing", "", http.StatusUnauthorized},
+		{"wrong scheme", "API-KEY " + token, http.StatusUnauthorized},
+		{"invalid", "Bearer invalid", http.StatusUnauthorized},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authorization != "" {
+				request.Header.Set("Authorization", tt.authorization)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			assert.Equal(t, tt.status, recorder.Code)
+			if tt.status == http.StatusOK {
+				assert.Equal(t, "user-1 role-1", recorder.Body.String())
+			} else {
+				assert.Equal(t, `Bearer realm="ucode"`, recorder.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	token := signToken(t, jwt.MapClaims{"user_id": "user-1", "exp": time.Now().Add(time.Hour).Unix()})
+	interceptor := UnaryServerInterceptor(&JWTValidator{Key: secret})
+
+	handler := func(ctx context.Context, req any) (any, error) {
+		return UserId(ctx), nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	resp, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", resp)
+
+	_, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestContextHelpers(t *testing.T) {
+	ctx := context.Background()
+	assert.Empty(t, UserId(ctx))
+	assert.Nil(t, Permissions(ctx))
+
+	_, ok := FromContext(ctx)
+	assert.False(t, ok)
+
+	ctx = NewContext(ctx, &User{UserId: "user-1", ClientTypeId: "ct-1"})
+	assert.Equal(t, "user-1", UserId(ctx))
+	assert.Equal(t, "ct-1", ClientTypeId(ctx))
+}
@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/spf13/cast"
+)
+
+// DefaultMethods are the signing methods JWTValidator accepts when Methods
+// is empty.
+var DefaultMethods = []string{"HS256", "HS384", "HS512", "RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// JWTValidator verifies u-code access tokens locally, without a round trip
+// to the auth service.
+type JWTValidator struct {
+	// Key verifies the signature: a []byte secret for HMAC, or the public
+	// key of an RSA, ECDSA or Ed25519 key pair.
+	Key any
+	// KeyFunc picks the key per token instead of Key, e.g. by its "kid"
+	// header to support key rotation.
+	KeyFunc jwt.Keyfunc
+	// Methods are the accepted signing methods, DefaultMethods when empty.
+	Methods []string
+	// Issuer and Audience are checked when not empty.
+	Issuer   string
+	Audience string
+	// Leeway tolerates clock skew when checking expiry.
+	Leeway time.Duration
+}
+
+func (v *JWTValidator) Validate(_ context.Context, token string) (*User, error) {
+	keyFunc := v.KeyFunc
+	if keyFunc == nil {
+		if v.Key == nil {
+			return nil, errors.New("auth: JWTValidator has no key")
+		}
+		keyFunc = func(*jwt.Token) (any, error) { return v.Key, nil }
+	}
+
+	methods := v.Methods
+	if len(methods) == 0 {
+		methods = DefaultMethods
+	}
+
+	options := []jwt.ParserOption{jwt.WithValidMethods(methods), jwt.WithLeeway(v.Leeway), jwt.WithExpirationRequired()}
+	if v.Issuer != "" {
+		options = append(options, jwt.WithIssuer(v.Issuer))
+	}
+	if v.Audience != "" {
+		options = append(options, jwt.WithAudience(v.Audience))
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(token, claims, keyFunc, options...); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	return userFromClaims(claims), nil
+}
+
+// userFromClaims maps the claims of a u-code access token to a User.
+func userFromClaims(claims jwt.MapClaims) *User {
+	user := &User{
+		UserId:        cast.ToString(claims["user_id"]),
+		UserIdAuth:    cast.ToString(claims["user_id_auth"]),
+		ProjectId:     cast.ToString(claims["project_id"]),
+		EnvironmentId: cast.ToString(claims["environment_id"]),
+		ResourceId:    cast.ToString(claims["resource_id"]),
+		RoleId:        cast.ToString(claims["role_id"]),
+		ClientTypeId:  cast.ToString(claims["client_type_id"]),
+		Permissions:   toMaps(claims["permissions"]),
+		Claims:        claims,
+	}
+
+	if user.UserId == "" {
+		user.UserId = cast.ToString(claims["sub"])
+	}
+	if data, ok := claims["data"].(map[string]any); ok {
+		user.UserData = data
+	}
+
+	return user
+}
+
+func toMaps(value any) []map[string]any {
+	items, ok := value.([]any)
+	if !ok {
+		return nil
+	}
+
+	maps := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		if m, ok := item.(map[string]any); ok {
+			maps = append(maps, m)
+		}
+	}
+
+	return maps
+}
@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// HTTPMiddleware rejects requests without a valid Bearer token with 401 and
+// serves the others with the resolved User in the request context.
+func HTTPMiddleware(validator Validator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, err := authenticate(r.Context(), validator, r.Header.Get("Authorization"))
+			if err != nil {
+				writeUnauthorized(w, err)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func writeUnauthorized(w http.ResponseWriter, err error) {
+	statusCode := http.StatusUnauthorized
+	if !errors.Is(err, ErrMissingToken) && !errors.Is(err, ErrInvalidToken) {
+		statusCode = http.StatusBadGateway
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("WWW-Authenticate", `Bearer realm="ucode"`)
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]any{
+		"status": "error",
+		"data":   map[string]any{"message": http.StatusText(statusCode), "error": err.Error()},
+	})
+}
+
+// UnaryServerInterceptor authenticates unary gRPC calls with the Bearer
+// token of their "authorization" metadata.
+func UnaryServerInterceptor(validator Validator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticateGRPC(ctx, validator)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor authenticates streaming gRPC calls like
+// UnaryServerInterceptor.
+func StreamServerInterceptor(validator Validator) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticateGRPC(stream.Context(), validator)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+func authenticateGRPC(ctx context.Context, validator Validator) (context.Context, error) {
+	var authorization string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			authorization = values[0]
+		}
+	}
+
+	ctx, err := authenticate(ctx, validator, authorization)
+	if err != nil {
+		code := codes.Unauthenticated
+		if !errors.Is(err, ErrMissingToken) && !errors.Is(err, ErrInvalidToken) {
+			code = codes.Unavailable
+		}
+		return ctx, status.Error(code, err.Error())
+	}
+
+	return ctx, nil
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
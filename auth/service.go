@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	ucodesdk "github.com/ucode-io/ucode_sdk"
+)
+
+// ServiceValidator validates tokens by asking the u-code auth service, so
+// revoked sessions are rejected and the user's current permissions are
+// returned.
+type ServiceValidator struct {
+	// SDK is the client sending the Auth().HasAccess calls.
+	SDK ucodesdk.UcodeApis
+}
+
+func (v *ServiceValidator) Validate(ctx context.Context, token string) (*User, error) {
+	resp, _, err := v.SDK.Auth().HasAccess(token).ExecContext(ctx)
+	if err != nil {
+		if errors.Is(err, ucodesdk.ErrUnauthorized) || errors.Is(err, ucodesdk.ErrForbidden) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+		}
+		return nil, err
+	}
+
+	return userFromLogin(resp), nil
+}
+
+// userFromLogin maps the data of a LoginResponse to a User.
+func userFromLogin(resp ucodesdk.LoginResponse) *User {
+	data := resp.Data
+	user := &User{
+		UserId:         data.UserId,
+		UserIdAuth:     data.UserIdAuth,
+		EnvironmentId:  data.EnvironmentId,
+		ResourceId:     data.ResourceId,
+		Role:           data.Role,
+		ClientType:     data.ClientType,
+		Permissions:    data.Permissions,
+		AppPermissions: data.AppPermissions,
+		UserData:       data.UserData,
+	}
+
+	if id, ok := data.Role["id"].(string); ok {
+		user.RoleId = id
+	}
+	if id, ok := data.ClientType["id"].(string); ok {
+		user.ClientTypeId = id
+	}
+	if data.User != nil {
+		user.ProjectId = data.User.ProjectId
+		if user.RoleId == "" {
+			user.RoleId = data.User.RoleId
+		}
+		if user.ClientTypeId == "" {
+			user.ClientTypeId = data.User.ClientTypeId
+		}
+	}
+
+	return user
+}
@@ -0,0 +1,196 @@
+/*
+Command ucode-fn runs a function locally against fixture files.
+
+	ucode-fn run [-pkg ./template/cmd] [-watch] [-fake] [-seed file] [-update] fixture...
+
+It builds and runs the function package with `go run`, whose main hands its
+handler to runner.Main. The default -pkg is the main of the function
+template, run from the directory holding the template. With -watch the
+fixtures are run again every time a .go file under -dir or a fixture
+changes; expected files are not watched, as -update rewrites them on every
+run.
+*/
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ucode-io/ucode_sdk/function/runner"
+)
+
+// defaultPkg is the package of the main of the function template.
+const defaultPkg = "./template/cmd"
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// options are the flags of the run command.
+type options struct {
+	pkg      string
+	dir      string
+	watch    bool
+	interval time.Duration
+	fake     bool
+	seed     string
+	update   bool
+	fixtures []string
+}
+
+// parseArgs parses the command line of the run command.
+func parseArgs(args []string, stderr io.Writer) (*options, error) {
+	if len(args) == 0 || args[0] != "run" {
+		return nil, fmt.Errorf("usage: ucode-fn run [flags] fixture...")
+	}
+
+	var opts options
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&opts.pkg, "pkg", defaultPkg, "package of the function main")
+	flags.StringVar(&opts.dir, "dir", ".", "directory watched for source changes")
+	flags.BoolVar(&opts.watch, "watch", false, "run again when sources or fixtures change")
+	flags.DurationVar(&opts.interval, "interval", 500*time.Millisecond, "polling interval of -watch")
+	flags.BoolVar(&opts.fake, "fake", false, "point the SDK at an in-memory u-code server")
+	flags.StringVar(&opts.seed, "seed", "", "JSON file with the objects of the fake server, keyed by collection")
+	flags.BoolVar(&opts.update, "update", false, "write the actual responses to the expected files")
+	if err := flags.Parse(args[1:]); err != nil {
+		return nil, err
+	}
+	opts.fixtures = flags.Args()
+
+	return &opts, nil
+}
+
+// goArgs returns the arguments of the `go run` command running the fixtures.
+func (o *options) goArgs() []string {
+	args := []string{"run", o.pkg, "run"}
+	if o.fake {
+		args = append(args, "-fake")
+	}
+	if o.seed != "" {
+		args = append(args, "-seed", o.seed)
+	}
+	if o.update {
+		args = append(args, "-update")
+	}
+
+	return append(args, o.fixtures...)
+}
+
+// watched returns the paths whose changes run the fixtures again.
+func (o *options) watched() []string {
+	return append([]string{o.dir}, o.fixtures...)
+}
+
+func run(args []string) int {
+	opts, err := parseArgs(args, os.Stderr)
+	if err != nil {
+		if err != flag.ErrHelp {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	code := goRun(ctx, opts.goArgs())
+	if !opts.watch {
+		return code
+	}
+
+	return watch(ctx, opts.watched(), opts.interval, code, func() int {
+		fmt.Printf("\n--- change detected at %s ---\n", time.Now().Format(time.TimeOnly))
+		return goRun(ctx, opts.goArgs())
+	})
+}
+
+// watch polls the files under paths every interval and calls rerun each
+// time they changed, until ctx is done. It returns the exit code of the last
+// run, code when rerun was never called.
+func watch(ctx context.Context, paths []string, interval time.Duration, code int, rerun func() int) int {
+	last := snapshot(paths)
+	for {
+		select {
+		case <-ctx.Done():
+			return code
+		case <-time.After(interval):
+		}
+
+		current := snapshot(paths)
+		if current.Equal(last) {
+			continue
+		}
+		last = current
+
+		code = rerun()
+	}
+}
+
+func goRun(ctx context.Context, args []string) int {
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return exitErr.ExitCode()
+		}
+		fmt.Fprintln(os.Stderr, "Error running go:", err)
+		return 1
+	}
+
+	return 0
+}
+
+// modTimes maps the watched files to their modification time.
+type modTimes map[string]time.Time
+
+func (m modTimes) Equal(other modTimes) bool {
+	if len(m) != len(other) {
+		return false
+	}
+	for path, modTime := range m {
+		if !other[path].Equal(modTime) {
+			return false
+		}
+	}
+	return true
+}
+
+// snapshot returns the modification times of the .go and .json files under
+// paths, expected files excepted.
+func snapshot(paths []string) modTimes {
+	times := modTimes{}
+	for _, root := range paths {
+		filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if d.IsDir() {
+				if path != root && strings.HasPrefix(d.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if ext := filepath.Ext(path); ext != ".go" && ext != ".json" || strings.HasSuffix(path, runner.ExpectedSuffix) {
+				return nil
+			}
+			if info, err := d.Info(); err == nil {
+				times[path] = info.ModTime()
+			}
+			return nil
+		})
+	}
+	return times
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		goArgs  []string
+		watched []string
+		err     bool
+	}{
+		{
+			name:    "defaults",
+			args:    []string{"run"},
+			goArgs:  []string{"run", "./template/cmd", "run"},
+			watched: []string{"."},
+		},
+		{
+			name:    "flags",
+			args:    []string{"run", "-pkg", "./cmd", "-fake", "-seed", "seed.json", "-update", "-dir", "src", "fixtures/", "a.json"},
+			goArgs:  []string{"run", "./cmd", "run", "-fake", "-seed", "seed.json", "-update", "fixtures/", "a.json"},
+			watched: []string{"src", "fixtures/", "a.json"},
+		},
+		{name: "no command", args: nil, err: true},
+		{name: "unknown command", args: []string{"test"}, err: true},
+		{name: "unknown flag", args: []string{"run", "-unknown"}, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := parseArgs(tt.args, io.Discard)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.goArgs, opts.goArgs())
+			assert.Equal(t, tt.watched, opts.watched())
+		})
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   []string
+		watched []string
+	}{
+		{
+			name:    "sources and fixtures",
+			files:   []string{"handler.go", "cmd/main.go", "fixtures/create.json"},
+			watched: []string{"cmd/main.go", "fixtures/create.json", "handler.go"},
+		},
+		{
+			name:    "expected files are skipped",
+			files:   []string{"fixtures/create.json", "fixtures/create.expected.json"},
+			watched: []string{"fixtures/create.json"},
+		},
+		{
+			name:    "other files and hidden directories are skipped",
+			files:   []string{"README.md", ".git/config.json", "handler.go"},
+			watched: []string{"handler.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, file := range tt.files {
+				writeFile(t, filepath.Join(dir, file), "{}")
+			}
+
+			var watched []string
+			for path := range snapshot([]string{dir}) {
+				rel, err := filepath.Rel(dir, path)
+				require.NoError(t, err)
+				watched = append(watched, filepath.ToSlash(rel))
+			}
+			slices.Sort(watched)
+
+			assert.Equal(t, tt.watched, watched)
+		})
+	}
+}
+
+func TestModTimesEqual(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Second)
+
+	tests := []struct {
+		name  string
+		a, b  modTimes
+		equal bool
+	}{
+		{"same", modTimes{"a.go": now}, modTimes{"a.go": now}, true},
+		{"modified", modTimes{"a.go": now}, modTimes{"a.go": later}, false},
+		{"added", modTimes{"a.go": now}, modTimes{"a.go": now, "b.go": now}, false},
+		{"renamed", modTimes{"a.go": now}, modTimes{"b.go": now}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.equal, tt.a.Equal(tt.b))
+		})
+	}
+}
+
+func TestWatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		touched string
+		runs    int
+	}{
+		{name: "source change", touched: "handler.go", runs: 1},
+		{name: "expected file change", touched: "create.expected.json", runs: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFile(t, filepath.Join(dir, "handler.go"), "package function")
+			writeFile(t, filepath.Join(dir, "create.expected.json"), "{}")
+
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+
+			// Keep touching the file, so a change lands after the first
+			// snapshot of watch whenever it is taken.
+			touched := make(chan struct{})
+			go func() {
+				defer close(touched)
+				for i := 1; ctx.Err() == nil; i++ {
+					at := time.Now().Add(time.Duration(i) * time.Hour)
+					os.Chtimes(filepath.Join(dir, tt.touched), at, at)
+					time.Sleep(5 * time.Millisecond)
+				}
+			}()
+
+			runs := 0
+			code := watch(ctx, []string{dir}, time.Millisecond, 0, func() int {
+				runs++
+				cancel()
+				return 1
+			})
+			<-touched
+
+			assert.Equal(t, tt.runs, runs)
+			assert.Equal(t, tt.runs, code)
+		})
+	}
+}
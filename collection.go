@@ -0,0 +1,237 @@
+package ucodesdk
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// TypedCollection exposes the items of one collection as values of T. It is
+// a thin layer over ItemsI: values are converted to and from the item maps
+// using the `ucode` struct tag of each field, falling back to its `json`
+// tag, so it works for both Mongo and Postgres projects.
+//
+//	type House struct {
+//		Guid      string `json:"guid"`
+//		Name      string `json:"name"`
+//		RoomCount int    `json:"room_count"`
+//	}
+//
+//	houses := ucodesdk.Collection[House](sdk, "houses")
+//	house, err := houses.GetSingle(ctx, guid)
+type TypedCollection[T any] struct {
+	sdk        UcodeApis
+	collection string
+	fields     fieldNames
+}
+
+// Collection returns a typed view of collection.
+func Collection[T any](sdk UcodeApis, collection string) *TypedCollection[T] {
+	return &TypedCollection[T]{
+		sdk:        sdk,
+		collection: collection,
+		fields:     fieldNamesOf(reflect.TypeFor[T]()),
+	}
+}
+
+// Items returns the untyped interface the collection is built on.
+func (c *TypedCollection[T]) Items() ItemsI {
+	return c.sdk.Items(c.collection)
+}
+
+// Create creates item and returns it as stored by u-code, with its guid.
+func (c *TypedCollection[T]) Create(ctx context.Context, item T) (T, error) {
+	var created T
+
+	data, err := c.encode(item)
+	if err != nil {
+		return created, err
+	}
+
+	resp, _, err := c.Items().Create(data).ExecContext(ctx)
+	if err != nil {
+		return created, err
+	}
+
+	err = c.decode(resp.Data.Data.Data, &created)
+	return created, err
+}
+
+// Update updates the item identified by the guid field of item.
+func (c *TypedCollection[T]) Update(ctx context.Context, item T) (T, error) {
+	var updated T
+
+	data, err := c.encode(item)
+	if err != nil {
+		return updated, err
+	}
+
+	resp, _, err := c.Items().Update(data).ExecSingleContext(ctx)
+	if err != nil {
+		return updated, err
+	}
+
+	err = c.decode(resp.Data.Data, &updated)
+	return updated, err
+}
+
+// GetSingle returns the item with the given guid.
+func (c *TypedCollection[T]) GetSingle(ctx context.Context, id string) (T, error) {
+	var item T
+
+	resp, _, err := c.Items().GetSingle(id).ExecContext(ctx)
+	if err != nil {
+		return item, err
+	}
+
+	err = c.decode(resp.Data.Data.Response, &item)
+	return item, err
+}
+
+// GetList returns one page of items. Options configure the underlying
+// builder, e.g. func(l *GetListItem) { l.Page(2).Limit(50) }.
+func (c *TypedCollection[T]) GetList(ctx context.Context, options ...func(*GetListItem)) ([]T, error) {
+	list := c.Items().GetList()
+	for _, option := range options {
+		option(list)
+	}
+
+	resp, _, err := list.ExecContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.decodeAll(resp.Data.Data.Response)
+}
+
+// Delete deletes the item with the given guid.
+func (c *TypedCollection[T]) Delete(ctx context.Context, id string) error {
+	_, err := c.Items().Delete().Single(id).ExecContext(ctx)
+	return err
+}
+
+func (c *TypedCollection[T]) encode(item T) (map[string]any, error) {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+
+	var object map[string]any
+	if err = json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+
+	return renameKeys(object, c.fields.toUcode), nil
+}
+
+func (c *TypedCollection[T]) decode(object map[string]any, item *T) error {
+	data, err := json.Marshal(renameKeys(object, c.fields.toJSON))
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, item)
+}
+
+func (c *TypedCollection[T]) decodeAll(objects []map[string]any) ([]T, error) {
+	items := make([]T, len(objects))
+	for i, object := range objects {
+		if err := c.decode(object, &items[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return items, nil
+}
+
+// fieldNames maps the json names of struct fields to their `ucode` names
+// and back, for the fields where the two differ.
+type fieldNames struct {
+	toUcode map[string]string
+	toJSON  map[string]string
+}
+
+// fieldNamesOf returns the field names of t. As in encoding/json, the
+// fields of embedded structs without a json name are promoted, a field
+// shadowing the fields of the same name nested deeper.
+func fieldNamesOf(t reflect.Type) fieldNames {
+	names := fieldNames{toUcode: map[string]string{}, toJSON: map[string]string{}}
+
+	shadowed := map[string]bool{}
+	visited := map[reflect.Type]bool{}
+	for level := []reflect.Type{t}; len(level) > 0; {
+		var embedded []reflect.Type
+		found := map[string]bool{}
+
+		for _, t := range level {
+			for t.Kind() == reflect.Pointer {
+				t = t.Elem()
+			}
+			if t.Kind() != reflect.Struct || visited[t] {
+				continue
+			}
+			visited[t] = true
+
+			for i := 0; i < t.NumField(); i++ {
+				field := t.Field(i)
+
+				jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+				if field.Anonymous && jsonName == "" {
+					fieldType := field.Type
+					if fieldType.Kind() == reflect.Pointer {
+						fieldType = fieldType.Elem()
+					}
+					if fieldType.Kind() == reflect.Struct {
+						embedded = append(embedded, fieldType)
+						continue
+					}
+				}
+
+				if !field.IsExported() || jsonName == "-" {
+					continue
+				}
+				if jsonName == "" {
+					jsonName = field.Name
+				}
+				if shadowed[jsonName] {
+					continue
+				}
+				found[jsonName] = true
+
+				ucodeName, _, _ := strings.Cut(field.Tag.Get("ucode"), ",")
+				if ucodeName == "" || ucodeName == "-" {
+					continue
+				}
+
+				if jsonName != ucodeName {
+					names.toUcode[jsonName] = ucodeName
+					names.toJSON[ucodeName] = jsonName
+				}
+			}
+		}
+
+		for name := range found {
+			shadowed[name] = true
+		}
+		level = embedded
+	}
+
+	return names
+}
+
+func renameKeys(object map[string]any, names map[string]string) map[string]any {
+	if len(names) == 0 || object == nil {
+		return object
+	}
+
+	renamed := make(map[string]any, len(object))
+	for key, value := range object {
+		if name, ok := names[key]; ok {
+			key = name
+		}
+		renamed[key] = value
+	}
+
+	return renamed
+}
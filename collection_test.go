@@ -0,0 +1,119 @@
+package ucodesdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testHouse struct {
+	Guid      string  `json:"guid,omitempty"`
+	Name      string  `json:"name"`
+	Price     float64 `json:"price"`
+	RoomCount int     `json:"room_count" ucode:"rooms"`
+}
+
+func TestCollection(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stored := map[string]any{"guid": "guid-1", "name": "house", "price": 15000, "rooms": 5}
+
+		switch r.Method {
+		case http.MethodPost:
+			var body ActionBody
+			json.NewDecoder(r.Body).Decode(&body)
+			received = body.Body
+			json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"data": map[string]any{"data": stored}}})
+		case http.MethodPut:
+			var body ActionBody
+			json.NewDecoder(r.Body).Decode(&body)
+			received = body.Body
+			json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"data": body.Body}})
+		case http.MethodGet:
+			if r.URL.Path == "/v2/items/houses/guid-1" {
+				json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"data": map[string]any{"response": stored}}})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"data": map[string]any{"response": []any{stored, stored}}}})
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	houses := Collection[testHouse](New(&Config{BaseURL: server.URL}), "houses")
+
+	created, err := houses.Create(ctx, testHouse{Name: "house", Price: 15000, RoomCount: 5})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": "house", "price": float64(15000), "rooms": float64(5)}, received)
+	assert.Equal(t, testHouse{Guid: "guid-1", Name: "house", Price: 15000, RoomCount: 5}, created)
+
+	created.Name = "updated"
+	updated, err := houses.Update(ctx, created)
+	assert.NoError(t, err)
+	assert.Equal(t, "guid-1", received["guid"])
+	assert.Equal(t, created, updated)
+
+	single, err := houses.GetSingle(ctx, "guid-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, single.RoomCount)
+
+	list, err := houses.GetList(ctx, func(l *GetListItem) { l.Limit(2) })
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, "guid-1", list[1].Guid)
+}
+
+func TestFieldNamesOf(t *testing.T) {
+	names := fieldNamesOf(reflect.TypeFor[testHouse]())
+	assert.Equal(t, map[string]string{"room_count": "rooms"}, names.toUcode)
+	assert.Equal(t, map[string]string{"rooms": "room_count"}, names.toJSON)
+
+	assert.Empty(t, fieldNamesOf(reflect.TypeFor[map[string]any]()).toUcode)
+}
+
+type testBase struct {
+	Guid      string `json:"guid" ucode:"id"`
+	CreatedAt string `json:"created_at" ucode:"created"`
+	Name      string `json:"name" ucode:"base_name"`
+}
+
+type testAudit struct {
+	UpdatedBy string `json:"updated_by" ucode:"editor"`
+}
+
+type testRoom struct {
+	testBase
+	*testAudit
+	Name  string   `json:"name"`
+	Floor int      `json:"floor" ucode:"level"`
+	Owner testBase `json:"owner"`
+}
+
+func TestFieldNamesOfEmbedded(t *testing.T) {
+	names := fieldNamesOf(reflect.TypeFor[testRoom]())
+	assert.Equal(t, map[string]string{
+		"guid":       "id",
+		"created_at": "created",
+		"updated_by": "editor",
+		"floor":      "level",
+	}, names.toUcode)
+	assert.Equal(t, "created_at", names.toJSON["created"])
+
+	collection := Collection[testRoom](nil, "rooms")
+	encoded, err := collection.encode(testRoom{testBase: testBase{Guid: "guid-1"}, testAudit: &testAudit{UpdatedBy: "john"}, Name: "kitchen"})
+	assert.NoError(t, err)
+	assert.Equal(t, "guid-1", encoded["id"])
+	assert.Equal(t, "john", encoded["editor"])
+	assert.Equal(t, "kitchen", encoded["name"])
+
+	var room testRoom
+	assert.NoError(t, collection.decode(map[string]any{"id": "guid-2", "created": "today", "level": 3}, &room))
+	assert.Equal(t, "guid-2", room.Guid)
+	assert.Equal(t, "today", room.CreatedAt)
+	assert.Equal(t, 3, room.Floor)
+}
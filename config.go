@@ -1,6 +1,10 @@
 package ucodesdk
 
 import (
+	"crypto/tls"
+	"log/slog"
+	"net/http"
+	"net/url"
 	"time"
 )
 
@@ -11,4 +15,76 @@ type Config struct {
 	ProjectId      string
 	RequestTimeout time.Duration
 	BaseAuthUrl    string
+
+	// HTTPClient is used for every request when set. RequestTimeout and the
+	// transport settings below are ignored in that case.
+	HTTPClient *http.Client
+	// Transport is the round tripper of the client built by New. When nil a
+	// clone of http.DefaultTransport tuned with TLSConfig, Proxy and
+	// MaxIdleConnsPerHost is used.
+	Transport           http.RoundTripper
+	TLSConfig           *tls.Config
+	Proxy               func(*http.Request) (*url.URL, error)
+	MaxIdleConnsPerHost int
+
+	// Retry enables automatic retries of idempotent calls. Nil disables
+	// them; see DefaultRetryPolicy for a sensible starting point.
+	Retry *RetryPolicy
+
+	// TokenSource, when set, authenticates calls with the Bearer token it
+	// returns instead of the X-API-KEY header. See NewTokenSource.
+	TokenSource TokenSource
+	// ResourceId and EnvironmentId are sent with every request as the
+	// Resource-Id and Environment-Id headers when set.
+	ResourceId    string
+	EnvironmentId string
+	// Headers are sent with every request. The headers set on a call with
+	// its Headers method take precedence over them.
+	Headers map[string]string
+
+	// Middleware wraps every call sent by the SDK, in order: the first one
+	// sees a call before the others and its result after them.
+	Middleware []Middleware
+	// ExecMiddleware wraps every Exec of a builder once, around all of its
+	// attempts. The Call it sees has an Attempt of 0 and only the headers
+	// set on the builder; the RoundTripFunc it wraps returns the Result of
+	// the last attempt.
+	ExecMiddleware []Middleware
+
+	// Logger, when set, receives a record for every call with its
+	// operation, URL, status, latency and attempt; headers and bodies are
+	// only added at Debug level. Credentials, tokens, passwords and OTP
+	// codes are redacted from the logged URLs, headers and bodies.
+	Logger *slog.Logger
+
+	// Limiter, when set, delays calls exceeding its rate or in-flight
+	// limits until they may be sent. See NewLimiter.
+	Limiter *Limiter
+}
+
+// newHTTPClient builds the client shared by every call of one SDK object.
+func newHTTPClient(cfg *Config) *http.Client {
+	if cfg.HTTPClient != nil {
+		return cfg.HTTPClient
+	}
+
+	transport := cfg.Transport
+	if transport == nil {
+		defaultTransport := http.DefaultTransport.(*http.Transport).Clone()
+		if cfg.TLSConfig != nil {
+			defaultTransport.TLSClientConfig = cfg.TLSConfig
+		}
+		if cfg.Proxy != nil {
+			defaultTransport.Proxy = cfg.Proxy
+		}
+		if cfg.MaxIdleConnsPerHost > 0 {
+			defaultTransport.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+		}
+		transport = defaultTransport
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   cfg.RequestTimeout,
+	}
 }
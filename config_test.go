@@ -0,0 +1,91 @@
+package ucodesdk
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingTransport struct {
+	calls atomic.Int32
+	next  http.RoundTripper
+}
+
+func (c *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	c.calls.Add(1)
+	return c.next.RoundTrip(r)
+}
+
+func TestConfigHTTPClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	filePath := filepath.Join(t.TempDir(), "upload.txt")
+	if err := os.WriteFile(filePath, []byte("content"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("HTTPClient", func(t *testing.T) {
+		transport := &countingTransport{next: http.DefaultTransport}
+		ucodeApi := New(&Config{
+			BaseURL:     server.URL,
+			BaseAuthUrl: server.URL,
+			HTTPClient:  &http.Client{Transport: transport},
+		})
+
+		_, _, err := ucodeApi.Items("houses").GetList().Exec()
+		assert.NoError(t, err)
+		_, _, err = ucodeApi.Auth().Login(map[string]any{}).Exec()
+		assert.NoError(t, err)
+		_, _, err = ucodeApi.Files().Upload(filePath).Exec()
+		assert.NoError(t, err)
+		_, _, err = ucodeApi.Function("path").Invoke(map[string]any{}).Exec()
+		assert.NoError(t, err)
+
+		assert.Equal(t, int32(4), transport.calls.Load())
+	})
+
+	t.Run("Transport", func(t *testing.T) {
+		transport := &countingTransport{next: http.DefaultTransport}
+		ucodeApi := New(&Config{BaseURL: server.URL, Transport: transport})
+
+		_, err := ucodeApi.Items("houses").Delete().Single("1").Exec()
+		assert.NoError(t, err)
+		_, err = ucodeApi.Files().Delete("1").Exec()
+		assert.NoError(t, err)
+
+		assert.Equal(t, int32(2), transport.calls.Load())
+	})
+}
+
+func TestConfigRequestTimeout(t *testing.T) {
+	server := newBlockingServer(t)
+	ucodeApi := New(&Config{BaseURL: server.URL, RequestTimeout: 50 * time.Millisecond})
+
+	start := time.Now()
+	_, _, err := ucodeApi.Items("houses").GetSingle("1").Exec()
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	client := newHTTPClient(&Config{RequestTimeout: time.Second, MaxIdleConnsPerHost: 32})
+	assert.Equal(t, time.Second, client.Timeout)
+
+	transport, ok := client.Transport.(*http.Transport)
+	if assert.True(t, ok) {
+		assert.Equal(t, 32, transport.MaxIdleConnsPerHost)
+		assert.NotSame(t, http.DefaultTransport, transport)
+	}
+
+	custom := &http.Client{}
+	assert.Same(t, custom, newHTTPClient(&Config{HTTPClient: custom}))
+}
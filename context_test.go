@@ -0,0 +1,140 @@
+package ucodesdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newBlockingServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	t.Cleanup(func() {
+		close(release)
+		server.Close()
+	})
+
+	return server
+}
+
+func TestExecContext(t *testing.T) {
+	server := newBlockingServer(t)
+	ucodeApi := New(&Config{BaseURL: server.URL, BaseAuthUrl: server.URL, AppId: "test_app_id"})
+
+	filePath := filepath.Join(t.TempDir(), "upload.txt")
+	if err := os.WriteFile(filePath, []byte("content"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := map[string]func(ctx context.Context) error{
+		"CreateItem": func(ctx context.Context) error {
+			_, _, err := ucodeApi.Items("houses").Create(map[string]any{"name": "house"}).ExecContext(ctx)
+			return err
+		},
+		"UpdateItemSingle": func(ctx context.Context) error {
+			_, _, err := ucodeApi.Items("houses").Update(map[string]any{"guid": "1"}).ExecSingleContext(ctx)
+			return err
+		},
+		"UpdateItemMultiple": func(ctx context.Context) error {
+			_, _, err := ucodeApi.Items("houses").Update(map[string]any{"objects": []any{}}).ExecMultipleContext(ctx, false)
+			return err
+		},
+		"DeleteItem": func(ctx context.Context) error {
+			_, err := ucodeApi.Items("houses").Delete().Single("1").ExecContext(ctx)
+			return err
+		},
+		"DeleteMultipleItem": func(ctx context.Context) error {
+			_, err := ucodeApi.Items("houses").Delete().Multiple([]string{"1"}).ExecContext(ctx)
+			return err
+		},
+		"GetSingleItem": func(ctx context.Context) error {
+			_, _, err := ucodeApi.Items("houses").GetSingle("1").ExecContext(ctx)
+			return err
+		},
+		"GetListItem": func(ctx context.Context) error {
+			_, _, err := ucodeApi.Items("houses").GetList().ExecContext(ctx)
+			return err
+		},
+		"GetListAggregation": func(ctx context.Context) error {
+			_, _, err := ucodeApi.Items("houses").GetList().Pipelines(map[string]any{}).ExecAggregationContext(ctx)
+			return err
+		},
+		"UploadFile": func(ctx context.Context) error {
+			_, _, err := ucodeApi.Files().Upload(filePath).ExecContext(ctx)
+			return err
+		},
+		"DeleteFile": func(ctx context.Context) error {
+			_, err := ucodeApi.Files().Delete("1").ExecContext(ctx)
+			return err
+		},
+		"InvokeFunction": func(ctx context.Context) error {
+			_, _, err := ucodeApi.Function("path").Invoke(map[string]any{}).ExecContext(ctx)
+			return err
+		},
+		"Register": func(ctx context.Context) error {
+			_, _, err := ucodeApi.Auth().Register(map[string]any{}).ExecContext(ctx)
+			return err
+		},
+		"ResetPassword": func(ctx context.Context) error {
+			_, err := ucodeApi.Auth().ResetPassword(map[string]any{}).ExecContext(ctx)
+			return err
+		},
+		"Login": func(ctx context.Context) error {
+			_, _, err := ucodeApi.Auth().Login(map[string]any{}).ExecContext(ctx)
+			return err
+		},
+		"LoginWithOption": func(ctx context.Context) error {
+			_, _, err := ucodeApi.Auth().Login(map[string]any{}).ExecWithOptionContext(ctx)
+			return err
+		},
+		"SendCode": func(ctx context.Context) error {
+			_, _, err := ucodeApi.Auth().SendCode(map[string]any{}).ExecContext(ctx)
+			return err
+		},
+	}
+
+	for name, call := range calls {
+		t.Run(name+"/deadline", func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			err := call(ctx)
+			assert.True(t, errors.Is(err, context.DeadlineExceeded), "got %v", err)
+			assert.Less(t, time.Since(start), 5*time.Second)
+		})
+
+		t.Run(name+"/cancel", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(50*time.Millisecond, cancel)
+
+			err := call(ctx)
+			assert.True(t, errors.Is(err, context.Canceled), "got %v", err)
+		})
+	}
+}
+
+func TestDoRequestContext(t *testing.T) {
+	server := newBlockingServer(t)
+	ucodeApi := New(&Config{BaseURL: server.URL})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := ucodeApi.DoRequestContext(ctx, server.URL, http.MethodGet, nil, nil)
+	assert.True(t, errors.Is(err, context.Canceled), "got %v", err)
+}
@@ -0,0 +1,112 @@
+package ucodesdk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinel errors matched by *APIError through errors.Is.
+var (
+	ErrBadRequest   = errors.New("ucode: bad request")
+	ErrValidation   = errors.New("ucode: validation failed")
+	ErrUnauthorized = errors.New("ucode: unauthorized")
+	ErrForbidden    = errors.New("ucode: forbidden")
+	ErrNotFound     = errors.New("ucode: not found")
+	ErrConflict     = errors.New("ucode: conflict")
+	ErrRateLimited  = errors.New("ucode: rate limited")
+	ErrServer       = errors.New("ucode: server error")
+)
+
+// APIError is returned by every Exec method when the u-code API answers with
+// a non 2xx status code. It carries the status code, the fields of the
+// u-code response envelope and the request that failed.
+type APIError struct {
+	StatusCode    int
+	Status        string
+	Description   string
+	CustomMessage string
+	Method        string
+	URL           string
+	Header        http.Header
+	Body          []byte
+}
+
+func (e *APIError) Error() string {
+	message := e.CustomMessage
+	if message == "" {
+		message = e.Description
+	}
+	if message == "" {
+		message = e.Status
+	}
+	if message == "" {
+		message = http.StatusText(e.StatusCode)
+	}
+
+	return fmt.Sprintf("ucode: %s %s: %d %s", e.Method, e.URL, e.StatusCode, message)
+}
+
+// Is reports whether the error belongs to the class described by target,
+// so callers can write errors.Is(err, ucodesdk.ErrNotFound).
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+
+	return false
+}
+
+// newAPIError builds an *APIError from a failed response, reading the
+// status, description and custom_message fields of the u-code envelope
+// when the body has one.
+func newAPIError(method, url string, statusCode int, header http.Header, body []byte) *APIError {
+	var envelope struct {
+		Status        any `json:"status"`
+		Description   any `json:"description"`
+		CustomMessage any `json:"custom_message"`
+	}
+	_ = json.Unmarshal(body, &envelope)
+
+	return &APIError{
+		StatusCode:    statusCode,
+		Status:        envelopeString(envelope.Status),
+		Description:   envelopeString(envelope.Description),
+		CustomMessage: envelopeString(envelope.CustomMessage),
+		Method:        method,
+		URL:           url,
+		Header:        header,
+		Body:          body,
+	}
+}
+
+func envelopeString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
@@ -0,0 +1,141 @@
+package ucodesdk
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIError(t *testing.T) {
+	var statusCode int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statusCode)
+		w.Write([]byte(`{"status":"NOT_FOUND","description":"object not found","custom_message":"Объект не найден","data":null}`))
+	}))
+	defer server.Close()
+
+	ucodeApi := New(&Config{BaseURL: server.URL, BaseAuthUrl: server.URL, AppId: "test_app_id"})
+
+	filePath := filepath.Join(t.TempDir(), "upload.txt")
+	if err := os.WriteFile(filePath, []byte("content"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("fields", func(t *testing.T) {
+		statusCode = http.StatusNotFound
+
+		_, response, err := ucodeApi.Items("houses").GetSingle("guid").Exec()
+
+		var apiErr *APIError
+		if assert.True(t, errors.As(err, &apiErr)) {
+			assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+			assert.Equal(t, "NOT_FOUND", apiErr.Status)
+			assert.Equal(t, "object not found", apiErr.Description)
+			assert.Equal(t, "Объект не найден", apiErr.CustomMessage)
+			assert.Equal(t, http.MethodGet, apiErr.Method)
+			assert.Equal(t, server.URL+"/v2/items/houses/guid?from-ofs=true", apiErr.URL)
+		}
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.NotErrorIs(t, err, ErrUnauthorized)
+		assert.Equal(t, "error", response.Status)
+	})
+
+	t.Run("sentinels", func(t *testing.T) {
+		cases := []struct {
+			statusCode int
+			target     error
+		}{
+			{http.StatusBadRequest, ErrBadRequest},
+			{http.StatusBadRequest, ErrValidation},
+			{http.StatusUnprocessableEntity, ErrValidation},
+			{http.StatusUnauthorized, ErrUnauthorized},
+			{http.StatusForbidden, ErrForbidden},
+			{http.StatusNotFound, ErrNotFound},
+			{http.StatusConflict, ErrConflict},
+			{http.StatusTooManyRequests, ErrRateLimited},
+			{http.StatusInternalServerError, ErrServer},
+			{http.StatusBadGateway, ErrServer},
+		}
+
+		for _, c := range cases {
+			err := error(&APIError{StatusCode: c.statusCode})
+			assert.ErrorIs(t, err, c.target, "status %d", c.statusCode)
+		}
+	})
+
+	t.Run("every exec", func(t *testing.T) {
+		statusCode = http.StatusUnauthorized
+
+		calls := map[string]func() error{
+			"CreateItem": func() error {
+				_, _, err := ucodeApi.Items("houses").Create(map[string]any{}).Exec()
+				return err
+			},
+			"UpdateItemSingle": func() error {
+				_, _, err := ucodeApi.Items("houses").Update(map[string]any{}).ExecSingle()
+				return err
+			},
+			"UpdateItemMultiple": func() error {
+				_, _, err := ucodeApi.Items("houses").Update(map[string]any{}).ExecMultiple(false)
+				return err
+			},
+			"DeleteItem": func() error {
+				_, err := ucodeApi.Items("houses").Delete().Single("1").Exec()
+				return err
+			},
+			"DeleteMultipleItem": func() error {
+				_, err := ucodeApi.Items("houses").Delete().Multiple([]string{"1"}).Exec()
+				return err
+			},
+			"GetListItem": func() error {
+				_, _, err := ucodeApi.Items("houses").GetList().Exec()
+				return err
+			},
+			"GetListAggregation": func() error {
+				_, _, err := ucodeApi.Items("houses").GetList().Pipelines(map[string]any{}).ExecAggregation()
+				return err
+			},
+			"UploadFile": func() error {
+				_, _, err := ucodeApi.Files().Upload(filePath).Exec()
+				return err
+			},
+			"DeleteFile": func() error {
+				_, err := ucodeApi.Files().Delete("1").Exec()
+				return err
+			},
+			"InvokeFunction": func() error {
+				_, _, err := ucodeApi.Function("path").Invoke(map[string]any{}).Exec()
+				return err
+			},
+			"Register": func() error {
+				_, _, err := ucodeApi.Auth().Register(map[string]any{}).Exec()
+				return err
+			},
+			"ResetPassword": func() error {
+				_, err := ucodeApi.Auth().ResetPassword(map[string]any{}).Exec()
+				return err
+			},
+			"Login": func() error {
+				_, _, err := ucodeApi.Auth().Login(map[string]any{}).Exec()
+				return err
+			},
+			"LoginWithOption": func() error {
+				_, _, err := ucodeApi.Auth().Login(map[string]any{}).ExecWithOption()
+				return err
+			},
+			"SendCode": func() error {
+				_, _, err := ucodeApi.Auth().SendCode(map[string]any{}).Exec()
+				return err
+			},
+		}
+
+		for name, call := range calls {
+			assert.ErrorIs(t, call(), ErrUnauthorized, name)
+		}
+	})
+}
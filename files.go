@@ -2,9 +2,11 @@ package ucodesdk
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"mime/multipart"
 	"net/http"
 	"os"
@@ -12,7 +14,7 @@ import (
 
 func (u *object) Files() FilesI {
 	return &APIFiles{
-		config: u.config,
+		sdk: u,
 	}
 }
 
@@ -45,19 +47,28 @@ type FilesI interface {
 
 func (f *APIFiles) Upload(filePath string) *UploadFile {
 	return &UploadFile{
-		config: f.config,
-		path:   filePath,
+		sdk:  f.sdk,
+		path: filePath,
 	}
 }
 
+func (c *UploadFile) Headers(headers map[string]string) *UploadFile {
+	c.headers = headers
+	return c
+}
+
 func (c *UploadFile) Exec() (CreateFileResponse, Response, error) {
+	return c.ExecContext(context.Background())
+}
+
+func (c *UploadFile) ExecContext(ctx context.Context) (CreateFileResponse, Response, error) {
 	var (
 		file          *os.File
 		fileBuffer    bytes.Buffer
 		writer        *multipart.Writer
 		response      = Response{Status: "done"}
 		createdObject CreateFileResponse
-		url           = fmt.Sprintf("%s/v1/files/folder_upload?folder_name=Media", c.config.BaseURL)
+		url           = fmt.Sprintf("%s/v1/files/folder_upload?folder_name=Media", c.sdk.config.BaseURL)
 	)
 
 	file, err := os.Open(c.path)
@@ -90,14 +101,18 @@ func (c *UploadFile) Exec() (CreateFileResponse, Response, error) {
 		return CreateFileResponse{}, response, err
 	}
 
-	var appId = c.config.AppId
-
-	header := map[string]string{
-		"authorization": "API-KEY",
-		"X-API-KEY":     appId,
-	}
-
-	createFileInByte, err := DoFileRequest(url, http.MethodPost, header, fileBuffer, writer)
+	header := map[string]string{}
+	maps.Copy(header, c.headers)
+	header["Content-Type"] = writer.FormDataContentType()
+
+	createFileInByte, err := c.sdk.send(ctx, apiCall{
+		operation:  OpFilesUpload,
+		url:        url,
+		method:     http.MethodPost,
+		body:       fileBuffer.Bytes(),
+		headers:    header,
+		authorized: true,
+	})
 	if err != nil {
 		response.Data = map[string]any{"description": string(createFileInByte), "message": "Can't send request", "error": err.Error()}
 		response.Status = "error"
@@ -116,25 +131,35 @@ func (c *UploadFile) Exec() (CreateFileResponse, Response, error) {
 
 func (f *APIFiles) Delete(fileID string) *DeleteFile {
 	return &DeleteFile{
-		config: f.config,
-		id:     fileID,
+		sdk: f.sdk,
+		id:  fileID,
 	}
 }
 
+func (a *DeleteFile) Headers(headers map[string]string) *DeleteFile {
+	a.headers = headers
+	return a
+}
+
 func (a *DeleteFile) Exec() (Response, error) {
+	return a.ExecContext(context.Background())
+}
+
+func (a *DeleteFile) ExecContext(ctx context.Context) (Response, error) {
 	var (
 		response = Response{Status: "done"}
-		url      = fmt.Sprintf("%s/v1/files/%s", a.config.BaseURL, a.id)
+		url      = fmt.Sprintf("%s/v1/files/%s", a.sdk.config.BaseURL, a.id)
 	)
 
-	var appId = a.config.AppId
-
-	header := map[string]string{
-		"authorization": "API-KEY",
-		"X-API-KEY":     appId,
-	}
-
-	_, err := DoRequest(url, http.MethodDelete, Request{Data: map[string]any{}}, header)
+	_, err := a.sdk.send(ctx, apiCall{
+		operation:  OpFilesDelete,
+		url:        url,
+		method:     http.MethodDelete,
+		body:       Request{Data: map[string]any{}},
+		headers:    a.headers,
+		authorized: true,
+		idempotent: true,
+	})
 	if err != nil {
 		response.Data = map[string]any{"message": "Error while deleting file", "error": err.Error()}
 		response.Status = "error"
@@ -145,25 +170,23 @@ func (a *DeleteFile) Exec() (Response, error) {
 }
 
 func DoFileRequest(url, method string, headers map[string]string, body bytes.Buffer, writer *multipart.Writer) ([]byte, error) {
-	request, err := http.NewRequest(method, url, &body)
-	if err != nil {
-		return nil, err
-	}
+	return DoFileRequestContext(context.Background(), url, method, headers, body, writer)
+}
 
-	for key, value := range headers {
-		request.Header.Add(key, value)
-	}
+// DoFileRequestContext is like DoFileRequest but the upload is bound to ctx.
+func DoFileRequestContext(ctx context.Context, url, method string, headers map[string]string, body bytes.Buffer, writer *multipart.Writer) ([]byte, error) {
+	return doFileRequest(ctx, http.DefaultClient, url, method, headers, body, writer)
+}
 
-	request.Header.Set("Content-Type", writer.FormDataContentType())
+func doFileRequest(ctx context.Context, client *http.Client, url, method string, headers map[string]string, body bytes.Buffer, writer *multipart.Writer) ([]byte, error) {
+	header := make(map[string]string, len(headers)+1)
+	maps.Copy(header, headers)
+	header["Content-Type"] = writer.FormDataContentType()
 
-	client := &http.Client{}
-	resp, err := client.Do(request)
-	if err != nil {
+	result, err := roundTrip(ctx, client, &Call{Method: method, URL: url, Headers: header, Body: body.Bytes(), Attempt: 1})
+	if result == nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	respByte, err := io.ReadAll(resp.Body)
 
-	return respByte, err
+	return result.Body, err
 }
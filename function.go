@@ -1,6 +1,7 @@
 package ucodesdk
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -8,8 +9,8 @@ import (
 
 func (u *object) Function(path string) FunctionI {
 	return &APIFunction{
-		config: u.config,
-		path:   path,
+		sdk:  u,
+		path: path,
 	}
 }
 
@@ -22,26 +23,38 @@ type FunctionI interface {
 
 func (f *APIFunction) Invoke(data map[string]any) *APIFunction {
 	return &APIFunction{
-		config:  f.config,
+		sdk:     f.sdk,
+		path:    f.path,
 		request: Request{Data: data},
+		headers: f.headers,
 	}
 }
 
+func (f *APIFunction) Headers(headers map[string]string) *APIFunction {
+	f.headers = headers
+	return f
+}
+
 func (f *APIFunction) Exec() (FunctionResponse, Response, error) {
+	return f.ExecContext(context.Background())
+}
+
+func (f *APIFunction) ExecContext(ctx context.Context) (FunctionResponse, Response, error) {
 	var (
 		response     = Response{Status: "done"}
 		invokeObject FunctionResponse
-		url          = fmt.Sprintf("%s/v1/invoke_function/%s", f.config.BaseURL, f.path)
+		url          = fmt.Sprintf("%s/v1/invoke_function/%s", f.sdk.config.BaseURL, f.path)
 	)
 
-	var appId = f.config.AppId
-
-	header := map[string]string{
-		"authorization": "API-KEY",
-		"X-API-KEY":     appId,
-	}
-
-	invokeFunctionResponseInByte, err := DoRequest(url, http.MethodPost, f.request, header)
+	invokeFunctionResponseInByte, err := f.sdk.send(ctx, apiCall{
+		operation:  OpFunctionInvoke,
+		collection: f.path,
+		url:        url,
+		method:     http.MethodPost,
+		body:       f.request,
+		headers:    f.headers,
+		authorized: true,
+	})
 	if err != nil {
 		response.Data = map[string]any{"description": string(invokeFunctionResponseInByte), "message": "Can't send request", "error": err.Error()}
 		response.Status = "error"
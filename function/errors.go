@@ -0,0 +1,51 @@
+package function
+
+import (
+	"net/http"
+
+	ucodesdk "github.com/ucode-io/ucode_sdk"
+)
+
+// Error is an error reported to the caller of a function with a given
+// status code, in the same shape as ucodesdk.ResponseError.
+type Error struct {
+	StatusCode  int
+	Message     string
+	Description any
+	Err         error
+}
+
+// NewError returns an *Error with a client facing message wrapping err.
+func NewError(statusCode int, message string, err error) *Error {
+	return &Error{StatusCode: statusCode, Message: message, Err: err}
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	if e.Message == "" {
+		return e.Err.Error()
+	}
+	return e.Message + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) statusCode() int {
+	if e.StatusCode == 0 {
+		return http.StatusInternalServerError
+	}
+	return e.StatusCode
+}
+
+func (e *Error) response() ucodesdk.Response {
+	data := map[string]any{"message": e.Message, "description": e.Description}
+	if e.Err != nil {
+		data["error"] = e.Err.Error()
+	}
+
+	return ucodesdk.Response{Status: "error", Data: data}
+}
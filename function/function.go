@@ -0,0 +1,212 @@
+/*
+Package function is the runtime of u-code serverless functions.
+
+Authors write a HandlerFunc and get a ready http.Handler that parses the
+incoming request, injects a configured SDK client, recovers panics and
+writes the standard response envelope:
+
+	func Handle() http.Handler {
+		return function.New(&function.Config{SDKConfig: &ucodesdk.Config{BaseURL: baseUrl}},
+			func(ctx context.Context, event *function.Event) (any, error) {
+				house, _, err := event.SDK.Items("houses").GetSingle(event.ObjectIds[0]).ExecContext(ctx)
+				if err != nil {
+					return nil, function.NewError(http.StatusBadGateway, "Error on getting house", err)
+				}
+				return house.Data.Data.Response, nil
+			},
+		)
+	}
+*/
+package function
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	ucodesdk "github.com/ucode-io/ucode_sdk"
+)
+
+// Event is one invocation of a function.
+type Event struct {
+	// Data is the "data" object of the request: app_id, method, object_data,
+	// object_ids, table_slug and user_id.
+	ucodesdk.Data
+	// Body is the raw request body.
+	Body []byte
+	// SDK is the client configured for this invocation.
+	SDK ucodesdk.UcodeApis
+	// Request is the incoming HTTP request.
+	Request *http.Request
+}
+
+// HandlerFunc handles an event. The returned value becomes the data of the
+// response; a returned error is reported with the status code of *Error or
+// 500 for any other error.
+type HandlerFunc func(ctx context.Context, event *Event) (any, error)
+
+type Config struct {
+	// SDK is injected into every event when set.
+	SDK ucodesdk.UcodeApis
+	// SDKConfig is used to build a client per event when SDK is nil. An
+	// empty AppId is filled with the app_id of the event.
+	SDKConfig *ucodesdk.Config
+	// Logger records the panics of the handler with their stack. Nil means
+	// slog.Default().
+	Logger *slog.Logger
+}
+
+// Handler serves a HandlerFunc over HTTP.
+type Handler struct {
+	config    Config
+	handler   HandlerFunc
+	transport http.RoundTripper
+}
+
+// New returns an http.Handler running handler for every request.
+func New(cfg *Config, handler HandlerFunc) *Handler {
+	h := &Handler{handler: handler}
+	if cfg != nil {
+		h.config = *cfg
+	}
+
+	if h.config.SDKConfig != nil && h.config.SDKConfig.HTTPClient == nil && h.config.SDKConfig.Transport == nil {
+		// share connections between the clients built for each event
+		h.transport = http.DefaultTransport.(*http.Transport).Clone()
+	}
+
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			// The panic value may hold internal details, so it is only
+			// logged and the caller gets a generic error.
+			h.logger().ErrorContext(r.Context(), "function panicked",
+				slog.String("panic", fmt.Sprint(recovered)),
+				slog.String("stack", string(debug.Stack())),
+			)
+			writeError(w, &Error{StatusCode: http.StatusInternalServerError, Message: "Internal error"})
+		}
+	}()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, NewError(http.StatusBadRequest, "Error on getting request body", err))
+		return
+	}
+
+	var request struct {
+		Data ucodesdk.Data `json:"data"`
+	}
+	if err = json.Unmarshal(body, &request); err != nil {
+		writeError(w, NewError(http.StatusBadRequest, "Error on unmarshal request", err))
+		return
+	}
+
+	event := &Event{
+		Data:    request.Data,
+		Body:    body,
+		SDK:     h.sdk(request.Data),
+		Request: r,
+	}
+
+	result, err := h.handler(r.Context(), event)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	data, err := responseData(result)
+	if err != nil {
+		writeError(w, NewError(http.StatusInternalServerError, "Error on marshalling response", err))
+		return
+	}
+
+	handleResponse(w, ucodesdk.Response{Status: "done", Data: data}, http.StatusOK)
+}
+
+func (h *Handler) logger() *slog.Logger {
+	if h.config.Logger != nil {
+		return h.config.Logger
+	}
+	return slog.Default()
+}
+
+func (h *Handler) sdk(data ucodesdk.Data) ucodesdk.UcodeApis {
+	if h.config.SDK != nil {
+		return h.config.SDK
+	}
+	if h.config.SDKConfig == nil {
+		return nil
+	}
+
+	cfg := *h.config.SDKConfig
+	if cfg.AppId == "" {
+		cfg.AppId = data.AppId
+	}
+	if h.transport != nil {
+		cfg.Transport = h.transport
+	}
+
+	return ucodesdk.New(&cfg)
+}
+
+// responseData converts the result of a handler into the data of the
+// response envelope. Values that are not JSON objects are returned under
+// the "result" key.
+func responseData(result any) (map[string]any, error) {
+	if result == nil {
+		return nil, nil
+	}
+	if data, ok := result.(map[string]any); ok {
+		return data, nil
+	}
+
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+
+	var data map[string]any
+	if err = json.Unmarshal(encoded, &data); err != nil {
+		return map[string]any{"result": result}, nil
+	}
+
+	return data, nil
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	var fnErr *Error
+	if !errors.As(err, &fnErr) {
+		fnErr = &Error{StatusCode: http.StatusInternalServerError, Message: "Internal error", Err: err}
+	}
+
+	handleResponse(w, fnErr.response(), fnErr.statusCode())
+}
+
+func handleResponse(w http.ResponseWriter, body any, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+
+	bodyByte, err := json.Marshal(body)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"status":"error","data":{"message":"Error marshalling response"}}`))
+		return
+	}
+
+	w.WriteHeader(statusCode)
+	w.Write(bodyByte)
+}
+
+// WithSDKConfig returns a copy of h that builds the client of every event
+// from cfg, e.g. to point a function at a local fake backend.
+func (h *Handler) WithSDKConfig(cfg *ucodesdk.Config) *Handler {
+	return New(&Config{SDKConfig: cfg, Logger: h.config.Logger}, h.handler)
+}
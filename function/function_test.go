@@ -0,0 +1,128 @@
+package function
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ucodesdk "github.com/ucode-io/ucode_sdk"
+	"github.com/ucode-io/ucode_sdk/ucodetest"
+)
+
+func serve(t *testing.T, handler http.Handler, body string) (int, ucodesdk.Response) {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+
+	var response ucodesdk.Response
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+	return recorder.Code, response
+}
+
+func TestHandler(t *testing.T) {
+	server := ucodetest.New(t)
+
+	config := server.Config()
+	config.AppId = ""
+	handler := New(&Config{SDKConfig: config}, func(ctx context.Context, event *Event) (any, error) {
+		assert.Equal(t, "houses", event.TableSlug)
+		assert.Equal(t, "CREATE", event.Method)
+		assert.Equal(t, []string{"id-1"}, event.ObjectIds)
+		assert.Equal(t, "user-1", event.UserId)
+
+		created, _, err := event.SDK.Items(event.TableSlug).Create(event.ObjectData).ExecContext(ctx)
+		if err != nil {
+			return nil, NewError(http.StatusBadGateway, "Error on creating house", err)
+		}
+		return created.Data.Data.Data, nil
+	})
+
+	code, response := serve(t, handler, `{"data":{"app_id":"`+server.AppId+`","method":"CREATE","table_slug":"houses","object_ids":["id-1"],"user_id":"user-1","object_data":{"name":"house"}}}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "done", response.Status)
+	assert.Equal(t, "house", response.Data["name"])
+	assert.Len(t, server.Objects("houses"), 1)
+
+	code, response = serve(t, handler, `{"data":{"app_id":"wrong","table_slug":"houses","method":"CREATE","object_ids":["id-1"],"user_id":"user-1","object_data":{}}}`)
+	assert.Equal(t, http.StatusBadGateway, code)
+	assert.Equal(t, "error", response.Status)
+	assert.Equal(t, "Error on creating house", response.Data["message"])
+}
+
+func TestHandlerErrors(t *testing.T) {
+	t.Run("invalid body", func(t *testing.T) {
+		handler := New(nil, func(ctx context.Context, event *Event) (any, error) {
+			t.Error("handler must not be called")
+			return nil, nil
+		})
+
+		code, response := serve(t, handler, `not json`)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "error", response.Status)
+		assert.Equal(t, "Error on unmarshal request", response.Data["message"])
+	})
+
+	t.Run("plain error", func(t *testing.T) {
+		handler := New(nil, func(ctx context.Context, event *Event) (any, error) {
+			return nil, errors.New("boom")
+		})
+
+		code, response := serve(t, handler, `{"data":{}}`)
+		assert.Equal(t, http.StatusInternalServerError, code)
+		assert.Equal(t, "boom", response.Data["error"])
+	})
+
+	t.Run("wrapped error", func(t *testing.T) {
+		handler := New(nil, func(ctx context.Context, event *Event) (any, error) {
+			return nil, errors.Join(errors.New("context"), &Error{StatusCode: http.StatusNotFound, Message: "House not found"})
+		})
+
+		code, response := serve(t, handler, `{"data":{}}`)
+		assert.Equal(t, http.StatusNotFound, code)
+		assert.Equal(t, "House not found", response.Data["message"])
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		var logged bytes.Buffer
+		handler := New(&Config{Logger: slog.New(slog.NewJSONHandler(&logged, nil))}, func(ctx context.Context, event *Event) (any, error) {
+			panic("secret-value")
+		})
+
+		code, response := serve(t, handler, `{"data":{}}`)
+		assert.Equal(t, http.StatusInternalServerError, code)
+		assert.Equal(t, "Internal error", response.Data["message"])
+		assert.NotContains(t, response.Data, "error")
+
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(logged.Bytes(), &record))
+		assert.Equal(t, "secret-value", record["panic"])
+		assert.Contains(t, record["stack"], "function_test.go")
+	})
+}
+
+func TestResponseData(t *testing.T) {
+	data, err := responseData(struct {
+		Name string `json:"name"`
+	}{Name: "house"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": "house"}, data)
+
+	data, err = responseData([]int{1, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"result": []int{1, 2}}, data)
+
+	data, err = responseData(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+}
@@ -0,0 +1,146 @@
+package function
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Timing is when a function runs relative to the operation that triggered it.
+type Timing string
+
+const (
+	Before Timing = "BEFORE"
+	After  Timing = "AFTER"
+	HTTP   Timing = "HTTP"
+)
+
+// Action is the operation that triggered a function.
+type Action string
+
+const (
+	ActionCreate          Action = "CREATE"
+	ActionUpdate          Action = "UPDATE"
+	ActionMultipleUpdate  Action = "MULTIPLE_UPDATE"
+	ActionDelete          Action = "DELETE"
+	ActionAppendMany2Many Action = "APPEND_MANY2MANY"
+	ActionDeleteMany2Many Action = "DELETE_MANY2MANY"
+)
+
+// AnyTable matches every table slug in Router.Handle.
+const AnyTable = "*"
+
+// Action returns the action of the event.
+func (e *Event) Action() Action {
+	return Action(strings.ToUpper(e.Method))
+}
+
+// BeforeHandlerFunc handles a BEFORE trigger. It may change
+// event.ObjectData, which is sent back as the object to store, or veto the
+// operation by returning an error, typically built with Veto.
+type BeforeHandlerFunc func(ctx context.Context, event *Event) error
+
+// Veto returns the error a BEFORE handler uses to reject the operation.
+func Veto(message string, description any) *Error {
+	return &Error{StatusCode: http.StatusBadRequest, Message: message, Description: description}
+}
+
+type route struct {
+	table  string
+	timing Timing
+	action Action
+}
+
+/*
+Router dispatches events to handlers by table slug, timing and action.
+
+The request of a trigger does not say whether it runs BEFORE or AFTER the
+operation: the timing is the one the function was attached with in u-code.
+Serve therefore takes it explicitly, and a function serving both BEFORE and
+AFTER triggers is deployed once per timing, each with the handler of its
+timing.
+
+	router := function.NewRouter()
+	router.Before("orders", function.ActionCreate, func(ctx context.Context, event *function.Event) error {
+		if cast.ToFloat64(event.ObjectData["amount"]) <= 0 {
+			return function.Veto("Amount must be positive", nil)
+		}
+		event.ObjectData["status"] = "new"
+		return nil
+	})
+	router.After("orders", function.ActionUpdate, notifyClient)
+	router.HTTP(report)
+
+	handler := function.New(cfg, router.Serve(function.Before))
+*/
+type Router struct {
+	routes   map[route]HandlerFunc
+	http     HandlerFunc
+	NotFound HandlerFunc
+}
+
+// NewRouter returns an empty router.
+func NewRouter() *Router {
+	return &Router{routes: map[route]HandlerFunc{}}
+}
+
+// Handle registers handler for table (or AnyTable), timing and action.
+func (r *Router) Handle(table string, timing Timing, action Action, handler HandlerFunc) {
+	r.routes[route{table: table, timing: timing, action: action}] = handler
+}
+
+// Before registers a BEFORE handler. The response carries the possibly
+// modified object_data.
+func (r *Router) Before(table string, action Action, handler BeforeHandlerFunc) {
+	r.Handle(table, Before, action, func(ctx context.Context, event *Event) (any, error) {
+		if err := handler(ctx, event); err != nil {
+			return nil, err
+		}
+		return map[string]any{"object_data": event.ObjectData}, nil
+	})
+}
+
+// After registers an AFTER handler.
+func (r *Router) After(table string, action Action, handler HandlerFunc) {
+	r.Handle(table, After, action, handler)
+}
+
+// HTTP registers the handler of events invoked directly over HTTP.
+func (r *Router) HTTP(handler HandlerFunc) {
+	r.http = handler
+}
+
+// Serve returns the handler of a function attached with timing, to be
+// passed to New. It dispatches the events to the handlers registered for
+// timing by their table slug and method; with HTTP, to the handler set with
+// r.HTTP.
+func (r *Router) Serve(timing Timing) HandlerFunc {
+	return func(ctx context.Context, event *Event) (any, error) {
+		return r.match(timing, event)(ctx, event)
+	}
+}
+
+func (r *Router) match(timing Timing, event *Event) HandlerFunc {
+	if timing == HTTP && r.http != nil {
+		return r.http
+	}
+
+	for _, table := range []string{event.TableSlug, AnyTable} {
+		if handler, ok := r.routes[route{table: table, timing: timing, action: event.Action()}]; ok {
+			return handler
+		}
+	}
+
+	if r.NotFound != nil {
+		return r.NotFound
+	}
+
+	return func(context.Context, *Event) (any, error) {
+		return nil, &Error{
+			StatusCode: http.StatusNotFound,
+			Message:    "No handler for event",
+			Err:        fmt.Errorf("no handler for %s %s %s", event.TableSlug, timing, event.Action()),
+		}
+	}
+}
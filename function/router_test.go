@@ -0,0 +1,82 @@
+package function
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter(t *testing.T) {
+	var called string
+	router := NewRouter()
+	router.Before("orders", ActionCreate, func(ctx context.Context, event *Event) error {
+		if event.ObjectData["amount"] == nil {
+			return Veto("Amount is required", map[string]any{"field": "amount"})
+		}
+		event.ObjectData["status"] = "new"
+		return nil
+	})
+	router.After("orders", ActionUpdate, func(ctx context.Context, event *Event) (any, error) {
+		called = "after orders update"
+		return nil, nil
+	})
+	router.Handle(AnyTable, After, ActionDelete, func(ctx context.Context, event *Event) (any, error) {
+		called = "after any delete " + event.TableSlug
+		return nil, nil
+	})
+	router.HTTP(func(ctx context.Context, event *Event) (any, error) {
+		return map[string]any{"http": true}, nil
+	})
+
+	before, after, direct := New(nil, router.Serve(Before)), New(nil, router.Serve(After)), New(nil, router.Serve(HTTP))
+
+	t.Run("before mutates object data", func(t *testing.T) {
+		code, response := serve(t, before, `{"data":{"table_slug":"orders","method":"CREATE","object_data":{"amount":10}}}`)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, map[string]any{"amount": float64(10), "status": "new"}, response.Data["object_data"])
+	})
+
+	t.Run("before vetoes", func(t *testing.T) {
+		code, response := serve(t, before, `{"data":{"table_slug":"orders","method":"create","object_data":{}}}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "error", response.Status)
+		assert.Equal(t, "Amount is required", response.Data["message"])
+		assert.Equal(t, map[string]any{"field": "amount"}, response.Data["description"])
+	})
+
+	t.Run("after", func(t *testing.T) {
+		code, _ := serve(t, after, `{"data":{"table_slug":"orders","method":"UPDATE"}}`)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "after orders update", called)
+	})
+
+	t.Run("any table", func(t *testing.T) {
+		code, _ := serve(t, after, `{"data":{"table_slug":"clients","method":"DELETE"}}`)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "after any delete clients", called)
+	})
+
+	t.Run("http", func(t *testing.T) {
+		code, response := serve(t, direct, `{"data":{"object_data":{}}}`)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, true, response.Data["http"])
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		code, response := serve(t, after, `{"data":{"table_slug":"orders","method":"APPEND_MANY2MANY"}}`)
+		assert.Equal(t, http.StatusNotFound, code)
+		assert.Equal(t, "no handler for orders AFTER APPEND_MANY2MANY", response.Data["error"])
+
+		// The timing is the one of the handler, whatever the event.
+		code, _ = serve(t, before, `{"data":{"table_slug":"orders","method":"UPDATE"}}`)
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+}
+
+func TestEventAction(t *testing.T) {
+	event := &Event{}
+	event.Method = "update"
+	assert.Equal(t, ActionUpdate, event.Action())
+}
@@ -0,0 +1,263 @@
+/*
+Package runner runs a function locally against fixture files.
+
+A function binary hands its handler to Main:
+
+	func main() {
+		runner.Main(function.Handle(), "template/request.json")
+	}
+
+and is then driven from the command line:
+
+	go run ./template/cmd run [-fake] [-seed seed.json] [-update] fixtures/
+
+Every fixture is a request body ({"data": {...}}) sent to the handler. When
+a file named like the fixture with the .expected.json extension exists, the
+response body is compared with it and a diff is printed on mismatch. With
+-fake the SDK injected into the handler points at an in-memory ucodetest
+server, optionally seeded from a {"collection": [objects...]} file.
+*/
+package runner
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ucode-io/ucode_sdk/function"
+	"github.com/ucode-io/ucode_sdk/ucodetest"
+)
+
+// ExpectedSuffix is the extension of the expected response of a fixture.
+const ExpectedSuffix = ".expected.json"
+
+// Main runs the command line in os.Args and exits. defaultFixtures are run
+// when no fixture is given.
+func Main(handler http.Handler, defaultFixtures ...string) {
+	os.Exit(Run(os.Args[1:], handler, os.Stdout, os.Stderr, defaultFixtures...))
+}
+
+// Run executes the command line args and returns the exit code: 0 when
+// every fixture passed, 1 when one failed and 2 on usage errors.
+func Run(args []string, handler http.Handler, stdout, stderr io.Writer, defaultFixtures ...string) int {
+	if len(args) > 0 && args[0] == "run" {
+		args = args[1:]
+	}
+
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: run [-fake] [-seed file] [-update] [fixture or directory ...]")
+		flags.PrintDefaults()
+	}
+	var (
+		fake   = flags.Bool("fake", false, "point the SDK at an in-memory u-code server")
+		seed   = flags.String("seed", "", "JSON file with the objects of the fake server, keyed by collection")
+		update = flags.Bool("update", false, "write the actual responses to the expected files")
+	)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = defaultFixtures
+	}
+
+	fixtures, err := findFixtures(paths)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error finding fixtures:", err)
+		return 2
+	}
+	if len(fixtures) == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	if *fake || *seed != "" {
+		server := ucodetest.NewServer()
+		defer server.Close()
+
+		if err = seedServer(server, *seed); err != nil {
+			fmt.Fprintln(stderr, "Error seeding fake server:", err)
+			return 2
+		}
+
+		fnHandler, ok := handler.(*function.Handler)
+		if !ok {
+			fmt.Fprintln(stderr, "-fake requires a handler built with function.New")
+			return 2
+		}
+		handler = fnHandler.WithSDKConfig(server.Config())
+	}
+
+	code := 0
+	for _, fixture := range fixtures {
+		if !runFixture(fixture, handler, *update, stdout) {
+			code = 1
+		}
+	}
+
+	return code
+}
+
+// findFixtures expands directories into the fixture files they contain.
+func findFixtures(paths []string) ([]string, error) {
+	var fixtures []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			fixtures = append(fixtures, path)
+			continue
+		}
+
+		matches, err := filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(matches)
+
+		for _, match := range matches {
+			if !strings.HasSuffix(match, ExpectedSuffix) {
+				fixtures = append(fixtures, match)
+			}
+		}
+	}
+
+	return fixtures, nil
+}
+
+func seedServer(server *ucodetest.Server, path string) error {
+	if path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var collections map[string][]map[string]any
+	if err = json.Unmarshal(data, &collections); err != nil {
+		return err
+	}
+
+	for collection, objects := range collections {
+		server.Seed(collection, objects...)
+	}
+
+	return nil
+}
+
+// runFixture sends fixture to handler and reports whether the response
+// matched the expected file.
+func runFixture(fixture string, handler http.Handler, update bool, stdout io.Writer) bool {
+	body, err := os.ReadFile(fixture)
+	if err != nil {
+		fmt.Fprintf(stdout, "FAIL %s\n\tError reading fixture: %v\n", fixture, err)
+		return false
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body)))
+
+	actual := indentJSON(recorder.Body.Bytes())
+	expectedPath := strings.TrimSuffix(fixture, filepath.Ext(fixture)) + ExpectedSuffix
+
+	if update {
+		if err = os.WriteFile(expectedPath, append(actual, '\n'), 0o644); err != nil {
+			fmt.Fprintf(stdout, "FAIL %s\n\tError writing %s: %v\n", fixture, expectedPath, err)
+			return false
+		}
+		fmt.Fprintf(stdout, "UPDATE %s (status %d)\n", expectedPath, recorder.Code)
+		return true
+	}
+
+	expected, err := os.ReadFile(expectedPath)
+	if os.IsNotExist(err) {
+		fmt.Fprintf(stdout, "RUN %s\nStatus: %d\nBody: %s\n", fixture, recorder.Code, actual)
+		return true
+	}
+	if err != nil {
+		fmt.Fprintf(stdout, "FAIL %s\n\tError reading %s: %v\n", fixture, expectedPath, err)
+		return false
+	}
+
+	expected = indentJSON(expected)
+	if bytes.Equal(expected, actual) {
+		fmt.Fprintf(stdout, "PASS %s (status %d)\n", fixture, recorder.Code)
+		return true
+	}
+
+	fmt.Fprintf(stdout, "FAIL %s (status %d)\n%s", fixture, recorder.Code, diff(string(expected), string(actual)))
+	return false
+}
+
+// indentJSON normalizes a JSON document so that equal documents compare
+// equal byte for byte. Invalid JSON is returned unchanged.
+func indentJSON(data []byte) []byte {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return bytes.TrimSpace(data)
+	}
+
+	indented, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return bytes.TrimSpace(data)
+	}
+
+	return indented
+}
+
+// diff returns a line diff of expected and actual, with removed lines
+// prefixed by "-" and added lines by "+".
+func diff(expected, actual string) string {
+	a := strings.Split(expected, "\n")
+	b := strings.Split(actual, "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			fmt.Fprintf(&out, "  %s\n", a[i])
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			fmt.Fprintf(&out, "- %s\n", a[i])
+			i++
+		default:
+			fmt.Fprintf(&out, "+ %s\n", b[j])
+			j++
+		}
+	}
+
+	return out.String()
+}
@@ -0,0 +1,103 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ucodesdk "github.com/ucode-io/ucode_sdk"
+	"github.com/ucode-io/ucode_sdk/function"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func countHouses(ctx context.Context, event *function.Event) (any, error) {
+	resp, _, err := event.SDK.Items(event.TableSlug).GetList().ExecContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"count": len(resp.Data.Data.Response)}, nil
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "echo.json"), `{"data":{"object_data":{"name":"house"}}}`)
+	writeFile(t, filepath.Join(dir, "echo.expected.json"), `{"status":"done","error":"","data":{"name":"house"}}`)
+
+	echo := function.New(nil, func(ctx context.Context, event *function.Event) (any, error) {
+		return event.ObjectData, nil
+	})
+
+	t.Run("pass", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		code := Run([]string{"run", dir}, echo, &stdout, &stderr)
+
+		assert.Equal(t, 0, code, stderr.String())
+		assert.Contains(t, stdout.String(), "PASS "+filepath.Join(dir, "echo.json"))
+	})
+
+	t.Run("fail with diff", func(t *testing.T) {
+		writeFile(t, filepath.Join(dir, "other.json"), `{"data":{"object_data":{"name":"flat"}}}`)
+		writeFile(t, filepath.Join(dir, "other.expected.json"), `{"status":"done","error":"","data":{"name":"house"}}`)
+		t.Cleanup(func() {
+			os.Remove(filepath.Join(dir, "other.json"))
+			os.Remove(filepath.Join(dir, "other.expected.json"))
+		})
+
+		var stdout, stderr bytes.Buffer
+		code := Run([]string{"run", dir}, echo, &stdout, &stderr)
+
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stdout.String(), "FAIL "+filepath.Join(dir, "other.json"))
+		assert.Contains(t, stdout.String(), `-     "name": "house"`)
+		assert.Contains(t, stdout.String(), `+     "name": "flat"`)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		fixture := filepath.Join(dir, "new.json")
+		writeFile(t, fixture, `{"data":{"object_data":{"name":"flat"}}}`)
+
+		var stdout, stderr bytes.Buffer
+		assert.Equal(t, 0, Run([]string{"run", "-update", fixture}, echo, &stdout, &stderr))
+
+		expected, err := os.ReadFile(filepath.Join(dir, "new.expected.json"))
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"status":"done","error":"","data":{"name":"flat"}}`, string(expected))
+	})
+
+	t.Run("fake backend", func(t *testing.T) {
+		fixture := filepath.Join(dir, "houses.json")
+		seed := filepath.Join(t.TempDir(), "seed.json")
+		writeFile(t, fixture, `{"data":{"table_slug":"houses"}}`)
+		writeFile(t, filepath.Join(dir, "houses.expected.json"), `{"status":"done","error":"","data":{"count":2}}`)
+		writeFile(t, seed, `{"houses":[{"name":"a"},{"name":"b"}]}`)
+
+		handler := function.New(&function.Config{SDKConfig: &ucodesdk.Config{BaseURL: "http://unreachable.invalid"}}, countHouses)
+
+		var stdout, stderr bytes.Buffer
+		code := Run([]string{"-fake", "-seed", seed, fixture}, handler, &stdout, &stderr)
+		assert.Equal(t, 0, code, stdout.String()+stderr.String())
+	})
+
+	t.Run("default fixtures", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		code := Run(nil, echo, &stdout, &stderr, filepath.Join(dir, "echo.json"))
+		assert.Equal(t, 0, code, stderr.String())
+	})
+
+	t.Run("missing fixture", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		assert.Equal(t, 2, Run([]string{"run", filepath.Join(dir, "missing.json")}, echo, &stdout, &stderr))
+	})
+}
+
+func TestDiff(t *testing.T) {
+	assert.Equal(t, "  a\n- b\n+ x\n  c\n", diff("a\nb\nc", "a\nx\nc"))
+}
@@ -2,6 +2,7 @@ package ucodesdk
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"os"
 	"testing"
@@ -47,9 +48,10 @@ func TestEndToEnd(t *testing.T) {
 			"X-API-KEY":     "test_app_id",
 		}
 
-		// Test successful request
+		// Test successful request, an unknown route is reported as *APIError
+		var apiErr *APIError
 		_, err := ucodeApi.DoRequest(baseUrl+"/test", "GET", nil, header)
-		if err != nil {
+		if err != nil && !errors.As(err, &apiErr) {
 			t.Errorf("Error on DoRequest: %v", err)
 			return
 		}
@@ -84,7 +86,7 @@ func TestEndToEnd(t *testing.T) {
 			"Custom-Header": "TestValue",
 		}
 		_, err = ucodeApi.DoRequest(baseUrl+"/test", "GET", nil, customHeaders)
-		if err != nil {
+		if err != nil && !errors.As(err, &apiErr) {
 			t.Errorf("Error on DoRequest with custom headers: %v", err)
 			return
 		}
@@ -587,7 +589,7 @@ func TestEndToEnd(t *testing.T) {
 			})
 		}
 
-		_, response, err := ucodeApi.Items("houses").Update(map[string]any{"objects": multipleUpdateRequest}).ExecMultiple()
+		_, response, err := ucodeApi.Items("houses").Update(map[string]any{"objects": multipleUpdateRequest}).ExecMultiple(false)
 		if err != nil {
 			errorResponse.Description = response.Data["description"]
 			errorResponse.ClientErrorMessage = "Error on MultipleUpdate"
@@ -631,7 +633,7 @@ func TestEndToEnd(t *testing.T) {
 		}
 
 		// Test with invalid parameters
-		_, _, err = ucodeApi.Items("").Update(map[string]any{"objects": []map[string]any{}}).ExecMultiple()
+		_, _, err = ucodeApi.Items("").Update(map[string]any{"objects": []map[string]any{}}).ExecMultiple(false)
 		if err == nil {
 			t.Error("Expected error for invalid parameters, got nil")
 			return
@@ -642,7 +644,7 @@ func TestEndToEnd(t *testing.T) {
 			A int
 			B func() // functions are not supported
 		}
-		_, _, err = ucodeApi.Items("houses").Update(map[string]any{"objects": MyStruct{}}).ExecMultiple()
+		_, _, err = ucodeApi.Items("houses").Update(map[string]any{"objects": MyStruct{}}).ExecMultiple(false)
 		if err == nil {
 			t.Error("error: invalid request given but work")
 			return
@@ -664,7 +666,7 @@ func TestEndToEnd(t *testing.T) {
 			})
 		}
 
-		_, response, err := ucodeApiPg.Items("houses").Update(map[string]any{"objects": multipleUpdateRequest}).ExecMultiple()
+		_, response, err := ucodeApiPg.Items("houses").Update(map[string]any{"objects": multipleUpdateRequest}).ExecMultiple(false)
 		if err != nil {
 			errorResponse.Description = response.Data["description"]
 			errorResponse.ClientErrorMessage = "Error on MultipleUpdate"
@@ -708,7 +710,7 @@ func TestEndToEnd(t *testing.T) {
 		}
 
 		// Test with invalid parameters
-		_, _, err = ucodeApiPg.Items("").Update(map[string]any{"objects": []map[string]any{}}).ExecMultiple()
+		_, _, err = ucodeApiPg.Items("").Update(map[string]any{"objects": []map[string]any{}}).ExecMultiple(false)
 		if err == nil {
 			t.Error("Expected error for invalid parameters, got nil")
 			return
@@ -719,7 +721,7 @@ func TestEndToEnd(t *testing.T) {
 			A int
 			B func() // functions are not supported
 		}
-		_, _, err = ucodeApiPg.Items("houses").Update(map[string]any{"objects": MyStruct{}}).ExecMultiple()
+		_, _, err = ucodeApiPg.Items("houses").Update(map[string]any{"objects": MyStruct{}}).ExecMultiple(false)
 		if err == nil {
 			t.Error("error: invalid request given but work")
 			return
@@ -0,0 +1,73 @@
+package ucodesdk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigHeaders(t *testing.T) {
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	sdk := New(&Config{
+		BaseURL:       server.URL,
+		BaseAuthUrl:   server.URL,
+		AppId:         "app",
+		ResourceId:    "resource-1",
+		EnvironmentId: "env-1",
+		Headers:       map[string]string{"X-Tenant": "tenant-1", "Environment-Id": "ignored"},
+	})
+
+	t.Run("applied to every call", func(t *testing.T) {
+		calls := map[string]func() error{
+			"items": func() error { _, _, err := sdk.Items("houses").GetList().Exec(); return err },
+			"function": func() error {
+				_, _, err := sdk.Function("notify").Invoke(nil).Exec()
+				return err
+			},
+			"files": func() error { _, err := sdk.Files().Delete("file-1").Exec(); return err },
+			"auth": func() error {
+				_, _, err := sdk.Auth().SendCode(map[string]any{"recipient": "+998900000000"}).Exec()
+				return err
+			},
+		}
+
+		for name, call := range calls {
+			require.NoError(t, call(), name)
+			assert.Equal(t, "resource-1", headers.Get("Resource-Id"), name)
+			assert.Equal(t, "env-1", headers.Get("Environment-Id"), name)
+			assert.Equal(t, "tenant-1", headers.Get("X-Tenant"), name)
+		}
+	})
+
+	t.Run("per call overrides", func(t *testing.T) {
+		_, _, err := sdk.Items("houses").GetList().Headers(map[string]string{"environment-id": "env-2", "X-Tenant": "tenant-2"}).Exec()
+		require.NoError(t, err)
+		assert.Equal(t, "env-2", headers.Get("Environment-Id"))
+		assert.Equal(t, "tenant-2", headers.Get("X-Tenant"))
+		assert.Equal(t, []string{"env-2"}, headers.Values("Environment-Id"))
+		assert.Equal(t, "app", headers.Get("X-Api-Key"))
+
+		_, err = sdk.Items("houses").Delete().Headers(map[string]string{"Resource-Id": "resource-2"}).Multiple([]string{"1"}).Exec()
+		require.NoError(t, err)
+		assert.Equal(t, "resource-2", headers.Get("Resource-Id"))
+
+		_, err = sdk.Items("houses").Delete().Multiple([]string{"1"}).Headers(map[string]string{"Resource-Id": "resource-3"}).Exec()
+		require.NoError(t, err)
+		assert.Equal(t, "resource-3", headers.Get("Resource-Id"))
+
+		for _, err := range sdk.Items("houses").GetList().Headers(map[string]string{"X-Tenant": "tenant-3"}).All(context.Background()) {
+			require.NoError(t, err)
+		}
+		assert.Equal(t, "tenant-3", headers.Get("X-Tenant"))
+	})
+}
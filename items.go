@@ -1,15 +1,20 @@
 package ucodesdk
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"net/http"
+	neturl "net/url"
+
+	"github.com/ucode-io/ucode_sdk/query"
 )
 
 func (u *object) Items(collection string) ItemsI {
 	return &APIItem{
 		collection: collection,
-		config:     u.config,
+		sdk:        u,
 	}
 }
 
@@ -73,7 +78,7 @@ type ItemsI interface {
 func (a *APIItem) Create(data map[string]any) *CreateItem {
 	return &CreateItem{
 		collection: a.collection,
-		config:     a.config,
+		sdk:        a.sdk,
 		data: ActionBody{
 			Body:        data,
 			DisableFaas: true,
@@ -86,23 +91,48 @@ func (c *CreateItem) DisableFaas(isDisable bool) *CreateItem {
 	return c
 }
 
+// IdempotencyKey sends key in the Idempotency-Key header, which makes the
+// call safe to retry under Config.Retry.
+func (c *CreateItem) IdempotencyKey(key string) *CreateItem {
+	c.idempotencyKey = key
+	return c
+}
+
+func (c *CreateItem) Headers(headers map[string]string) *CreateItem {
+	c.headers = headers
+	return c
+}
+
 func (c *CreateItem) Exec() (Datas, Response, error) {
+	return c.ExecContext(context.Background())
+}
+
+func (c *CreateItem) ExecContext(ctx context.Context) (Datas, Response, error) {
 	var (
 		response = Response{
 			Status: "done",
 		}
 		createdObject Datas
-		url           = fmt.Sprintf("%s/v2/items/%s?from-ofs=%t", c.config.BaseURL, c.collection, c.data.DisableFaas)
+		url           = fmt.Sprintf("%s/v2/items/%s?from-ofs=%t", c.sdk.config.BaseURL, c.collection, c.data.DisableFaas)
 	)
 
-	var appId = c.config.AppId
+	header := map[string]string{}
+	maps.Copy(header, c.headers)
 
-	header := map[string]string{
-		"authorization": "API-KEY",
-		"X-API-KEY":     appId,
+	if c.idempotencyKey != "" {
+		header["Idempotency-Key"] = c.idempotencyKey
 	}
 
-	createObjectResponseInByte, err := DoRequest(url, http.MethodPost, c.data, header)
+	createObjectResponseInByte, err := c.sdk.send(ctx, apiCall{
+		operation:  OpItemsCreate,
+		collection: c.collection,
+		url:        url,
+		method:     http.MethodPost,
+		body:       c.data,
+		headers:    header,
+		authorized: true,
+		idempotent: c.idempotencyKey != "",
+	})
 	if err != nil {
 		response.Data = map[string]any{"description": string(createObjectResponseInByte), "message": "Can't send request", "error": err.Error()}
 		response.Status = "error"
@@ -123,7 +153,7 @@ func (c *CreateItem) Exec() (Datas, Response, error) {
 func (a *APIItem) Update(data map[string]any) *UpdateItem {
 	return &UpdateItem{
 		collection: a.collection,
-		config:     a.config,
+		sdk:        a.sdk,
 		data:       ActionBody{Body: data, DisableFaas: true},
 	}
 }
@@ -133,23 +163,48 @@ func (a *UpdateItem) DisableFaas(isDisable bool) *UpdateItem {
 	return a
 }
 
+// IdempotencyKey sends key in the Idempotency-Key header, which makes the
+// call safe to retry under Config.Retry.
+func (a *UpdateItem) IdempotencyKey(key string) *UpdateItem {
+	a.idempotencyKey = key
+	return a
+}
+
+func (a *UpdateItem) Headers(headers map[string]string) *UpdateItem {
+	a.headers = headers
+	return a
+}
+
 func (u *UpdateItem) ExecSingle() (ClientApiUpdateResponse, Response, error) {
+	return u.ExecSingleContext(context.Background())
+}
+
+func (u *UpdateItem) ExecSingleContext(ctx context.Context) (ClientApiUpdateResponse, Response, error) {
 	var (
 		response = Response{
 			Status: "done",
 		}
 		updateObject ClientApiUpdateResponse
-		url          = fmt.Sprintf("%s/v2/items/%s?from-ofs=%t", u.config.BaseURL, u.collection, u.data.DisableFaas)
+		url          = fmt.Sprintf("%s/v2/items/%s?from-ofs=%t", u.sdk.config.BaseURL, u.collection, u.data.DisableFaas)
 	)
 
-	var appId = u.config.AppId
+	header := map[string]string{}
+	maps.Copy(header, u.headers)
 
-	header := map[string]string{
-		"authorization": "API-KEY",
-		"X-API-KEY":     appId,
+	if u.idempotencyKey != "" {
+		header["Idempotency-Key"] = u.idempotencyKey
 	}
 
-	updateObjectResponseInByte, err := DoRequest(url, http.MethodPut, u.data, header)
+	updateObjectResponseInByte, err := u.sdk.send(ctx, apiCall{
+		operation:  OpItemsUpdate,
+		collection: u.collection,
+		url:        url,
+		method:     http.MethodPut,
+		body:       u.data,
+		headers:    header,
+		authorized: true,
+		idempotent: u.idempotencyKey != "",
+	})
 	if err != nil {
 		response.Data = map[string]any{"description": string(updateObjectResponseInByte), "message": "Error while updating object", "error": err.Error()}
 		response.Status = "error"
@@ -167,22 +222,35 @@ func (u *UpdateItem) ExecSingle() (ClientApiUpdateResponse, Response, error) {
 }
 
 func (a *UpdateItem) ExecMultiple(blockBuilder bool) (ClientApiMultipleUpdateResponse, Response, error) {
+	return a.ExecMultipleContext(context.Background(), blockBuilder)
+}
+
+func (a *UpdateItem) ExecMultipleContext(ctx context.Context, blockBuilder bool) (ClientApiMultipleUpdateResponse, Response, error) {
 	var (
 		response = Response{
 			Status: "done",
 		}
 		multipleUpdateObject ClientApiMultipleUpdateResponse
-		url                  = fmt.Sprintf("%s/v2/items/%s?from-ofs=%t&block_builder=%t", a.config.BaseURL, a.collection, a.data.DisableFaas, blockBuilder)
+		url                  = fmt.Sprintf("%s/v2/items/%s?from-ofs=%t&block_builder=%t", a.sdk.config.BaseURL, a.collection, a.data.DisableFaas, blockBuilder)
 	)
 
-	var appId = a.config.AppId
+	header := map[string]string{}
+	maps.Copy(header, a.headers)
 
-	header := map[string]string{
-		"authorization": "API-KEY",
-		"X-API-KEY":     appId,
+	if a.idempotencyKey != "" {
+		header["Idempotency-Key"] = a.idempotencyKey
 	}
 
-	multipleUpdateObjectsResponseInByte, err := DoRequest(url, http.MethodPatch, a.data, header)
+	multipleUpdateObjectsResponseInByte, err := a.sdk.send(ctx, apiCall{
+		operation:  OpItemsUpdateMultiple,
+		collection: a.collection,
+		url:        url,
+		method:     http.MethodPatch,
+		body:       a.data,
+		headers:    header,
+		authorized: true,
+		idempotent: a.idempotencyKey != "",
+	})
 	if err != nil {
 		response.Data = map[string]any{"description": string(multipleUpdateObjectsResponseInByte), "message": "Error while multiple updating objects", "error": err.Error()}
 		response.Status = "error"
@@ -203,7 +271,7 @@ func (a *UpdateItem) ExecMultiple(blockBuilder bool) (ClientApiMultipleUpdateRes
 func (a *APIItem) Delete() *DeleteItem {
 	return &DeleteItem{
 		collection:  a.collection,
-		config:      a.config,
+		sdk:         a.sdk,
 		disableFaas: true,
 	}
 }
@@ -221,28 +289,40 @@ func (a *DeleteItem) Single(id string) *DeleteItem {
 func (a *DeleteItem) Multiple(ids []string) *DeleteMultipleItem {
 	return &DeleteMultipleItem{
 		collection:  a.collection,
-		config:      a.config,
+		sdk:         a.sdk,
 		disableFaas: a.disableFaas,
 		ids:         ids,
+		headers:     a.headers,
 	}
 }
 
+func (a *DeleteItem) Headers(headers map[string]string) *DeleteItem {
+	a.headers = headers
+	return a
+}
+
 func (a *DeleteItem) Exec() (Response, error) {
+	return a.ExecContext(context.Background())
+}
+
+func (a *DeleteItem) ExecContext(ctx context.Context) (Response, error) {
 	var (
 		response = Response{
 			Status: "done",
 		}
-		url = fmt.Sprintf("%s/v2/items/%s/%v?from-ofs=%t", a.config.BaseURL, a.collection, a.id, a.disableFaas)
+		url = fmt.Sprintf("%s/v2/items/%s/%v?from-ofs=%t", a.sdk.config.BaseURL, a.collection, a.id, a.disableFaas)
 	)
 
-	var appId = a.config.AppId
-
-	header := map[string]string{
-		"authorization": "API-KEY",
-		"X-API-KEY":     appId,
-	}
-
-	_, err := DoRequest(url, http.MethodDelete, Request{Data: map[string]any{}}, header)
+	_, err := a.sdk.send(ctx, apiCall{
+		operation:  OpItemsDelete,
+		collection: a.collection,
+		url:        url,
+		method:     http.MethodDelete,
+		body:       Request{Data: map[string]any{}},
+		headers:    a.headers,
+		authorized: true,
+		idempotent: true,
+	})
 	if err != nil {
 		response.Data = map[string]any{"message": "Error while deleting object", "error": err.Error()}
 		response.Status = "error"
@@ -252,28 +332,39 @@ func (a *DeleteItem) Exec() (Response, error) {
 	return response, nil
 }
 
+func (a *DeleteMultipleItem) Headers(headers map[string]string) *DeleteMultipleItem {
+	a.headers = headers
+	return a
+}
+
 func (a *DeleteMultipleItem) Exec() (Response, error) {
+	return a.ExecContext(context.Background())
+}
+
+func (a *DeleteMultipleItem) ExecContext(ctx context.Context) (Response, error) {
 	var (
 		response = Response{
 			Status: "done",
 		}
-		url = fmt.Sprintf("%s/v2/items/%s?from-ofs=%t", a.config.BaseURL, a.collection, a.disableFaas)
+		url = fmt.Sprintf("%s/v2/items/%s?from-ofs=%t", a.sdk.config.BaseURL, a.collection, a.disableFaas)
 	)
 
-	var appId = a.config.AppId
-
-	header := map[string]string{
-		"authorization": "API-KEY",
-		"X-API-KEY":     appId,
-	}
-
 	if len(a.ids) == 0 {
 		response.Data = map[string]any{"message": "Error while deleting objects", "error": "ids is empty"}
 		response.Status = "error"
 		return response, fmt.Errorf("ids is empty")
 	}
 
-	_, err := DoRequest(url, http.MethodDelete, map[string]any{"ids": a.ids}, header)
+	_, err := a.sdk.send(ctx, apiCall{
+		operation:  OpItemsDeleteMultiple,
+		collection: a.collection,
+		url:        url,
+		method:     http.MethodDelete,
+		body:       map[string]any{"ids": a.ids},
+		headers:    a.headers,
+		authorized: true,
+		idempotent: true,
+	})
 	if err != nil {
 		response.Data = map[string]any{"message": "Error while deleting objects", "error": err.Error()}
 		response.Status = "error"
@@ -287,12 +378,21 @@ func (a *DeleteMultipleItem) Exec() (Response, error) {
 func (a *APIItem) GetSingle(id string) *GetSingleItem {
 	return &GetSingleItem{
 		collection: a.collection,
-		config:     a.config,
+		sdk:        a.sdk,
 		guid:       id,
 	}
 }
 
+func (a *GetSingleItem) Headers(headers map[string]string) *GetSingleItem {
+	a.headers = headers
+	return a
+}
+
 func (a *GetSingleItem) Exec() (ClientApiResponse, Response, error) {
+	return a.ExecContext(context.Background())
+}
+
+func (a *GetSingleItem) ExecContext(ctx context.Context) (ClientApiResponse, Response, error) {
 	if a.guid == "" {
 		return ClientApiResponse{}, Response{Status: "error", Data: map[string]any{"message": "guid is empty"}}, fmt.Errorf("guid is empty")
 	}
@@ -300,17 +400,18 @@ func (a *GetSingleItem) Exec() (ClientApiResponse, Response, error) {
 	var (
 		response  = Response{Status: "done"}
 		getObject ClientApiResponse
-		url       = fmt.Sprintf("%s/v2/items/%s/%v?from-ofs=%t", a.config.BaseURL, a.collection, a.guid, true)
+		url       = fmt.Sprintf("%s/v2/items/%s/%v?from-ofs=%t", a.sdk.config.BaseURL, a.collection, a.guid, true)
 	)
 
-	var appId = a.config.AppId
-
-	header := map[string]string{
-		"authorization": "API-KEY",
-		"X-API-KEY":     appId,
-	}
-
-	resByte, err := DoRequest(url, http.MethodGet, nil, header)
+	resByte, err := a.sdk.send(ctx, apiCall{
+		operation:  OpItemsGetSingle,
+		collection: a.collection,
+		url:        url,
+		method:     http.MethodGet,
+		headers:    a.headers,
+		authorized: true,
+		idempotent: true,
+	})
 	if err != nil {
 		response.Data = map[string]any{"description": string(resByte), "message": "Can't sent request", "error": err.Error()}
 		response.Status = "error"
@@ -333,7 +434,7 @@ func (a *GetSingleItem) Exec() (ClientApiResponse, Response, error) {
 func (a *APIItem) GetList() *GetListItem {
 	return &GetListItem{
 		collection: a.collection,
-		config:     a.config,
+		sdk:        a.sdk,
 		request:    Request{Data: map[string]any{}},
 	}
 }
@@ -343,7 +444,7 @@ func (a *GetListItem) Limit(limit int) *GetListItem {
 		limit = 10
 	}
 	a.limit = limit
-	a.request.Data["offset"] = (a.page - 1) * limit
+	a.request.Data["offset"] = (max(a.page, 1) - 1) * limit
 	a.request.Data["limit"] = limit
 	return a
 }
@@ -364,6 +465,24 @@ func (a *GetListItem) Filter(filter map[string]any) *GetListItem {
 	return a
 }
 
+// Where adds type-safe filters built with the query package. Several
+// conditions, and several calls to Where, are combined with query.And.
+func (a *GetListItem) Where(conditions ...query.Condition) *GetListItem {
+	built := query.And(conditions...).Build()
+
+	// Pull the filters already set on the keys of built, and any $and, so
+	// that they are combined with the new conditions instead of replaced.
+	existing := query.Filter{}
+	for key := range a.request.Data {
+		if _, ok := built[key]; ok || key == "$and" {
+			existing[key] = a.request.Data[key]
+			delete(a.request.Data, key)
+		}
+	}
+
+	return a.Filter(query.And(existing, query.Filter(built)).Build())
+}
+
 func (a *GetListItem) Search(search string) *GetListItem {
 	a.request.Data["search"] = search
 	return a
@@ -382,8 +501,9 @@ func (a *GetListItem) ViewFields(fields []string) *GetListItem {
 func (a *GetListItem) Pipelines(query map[string]any) *GetListAggregation {
 	return &GetListAggregation{
 		collection: a.collection,
-		config:     a.config,
+		sdk:        a.sdk,
 		request:    Request{Data: query},
+		headers:    a.headers,
 	}
 }
 
@@ -392,11 +512,20 @@ func (a *GetListItem) WithRelations(with bool) *GetListItem {
 	return a
 }
 
+func (a *GetListItem) Headers(headers map[string]string) *GetListItem {
+	a.headers = headers
+	return a
+}
+
 func (a *GetListItem) Exec() (GetListClientApiResponse, Response, error) {
+	return a.ExecContext(context.Background())
+}
+
+func (a *GetListItem) ExecContext(ctx context.Context) (GetListClientApiResponse, Response, error) {
 	var (
 		response = Response{Status: "done"}
 		listSlim GetListClientApiResponse
-		url      = fmt.Sprintf("%s/v2/items/%s?from-ofs=%t", a.config.BaseURL, a.collection, true)
+		url      = fmt.Sprintf("%s/v2/items/%s?from-ofs=%t", a.sdk.config.BaseURL, a.collection, true)
 	)
 
 	reqObject, err := json.Marshal(a.request.Data)
@@ -414,16 +543,22 @@ func (a *GetListItem) Exec() (GetListClientApiResponse, Response, error) {
 		a.limit = 10
 	}
 
-	url = fmt.Sprintf("%s&data=%s&offset=%d&limit=%d", url, string(reqObject), (a.page-1)*a.limit, a.limit)
-
-	var appId = a.config.AppId
-
-	header := map[string]string{
-		"authorization": "API-KEY",
-		"X-API-KEY":     appId,
+	offset := (a.page - 1) * a.limit
+	if a.offset > 0 {
+		offset = a.offset
 	}
 
-	getListResponseInByte, err := DoRequest(url, http.MethodGet, nil, header)
+	url = fmt.Sprintf("%s&data=%s&offset=%d&limit=%d", url, neturl.QueryEscape(string(reqObject)), offset, a.limit)
+
+	getListResponseInByte, err := a.sdk.send(ctx, apiCall{
+		operation:  OpItemsGetList,
+		collection: a.collection,
+		url:        url,
+		method:     http.MethodGet,
+		headers:    a.headers,
+		authorized: true,
+		idempotent: true,
+	})
 	if err != nil {
 		response.Data = map[string]any{"description": string(getListResponseInByte), "message": "Can't sent request", "error": err.Error()}
 		response.Status = "error"
@@ -440,21 +575,32 @@ func (a *GetListItem) Exec() (GetListClientApiResponse, Response, error) {
 	return listSlim, response, nil
 }
 
+func (a *GetListAggregation) Headers(headers map[string]string) *GetListAggregation {
+	a.headers = headers
+	return a
+}
+
 func (a *GetListAggregation) ExecAggregation() (GetListAggregationClientApiResponse, Response, error) {
+	return a.ExecAggregationContext(context.Background())
+}
+
+func (a *GetListAggregation) ExecAggregationContext(ctx context.Context) (GetListAggregationClientApiResponse, Response, error) {
 	var (
 		response           = Response{Status: "done"}
 		getListAggregation GetListAggregationClientApiResponse
-		url                = fmt.Sprintf("%s/v2/items/%s/aggregation", a.config.BaseURL, a.collection)
+		url                = fmt.Sprintf("%s/v2/items/%s/aggregation", a.sdk.config.BaseURL, a.collection)
 	)
 
-	var appId = a.config.AppId
-
-	header := map[string]string{
-		"authorization": "API-KEY",
-		"X-API-KEY":     appId,
-	}
-
-	getListAggregationResponseInByte, err := DoRequest(url, http.MethodPost, a.request, header)
+	getListAggregationResponseInByte, err := a.sdk.send(ctx, apiCall{
+		operation:  OpItemsAggregation,
+		collection: a.collection,
+		url:        url,
+		method:     http.MethodPost,
+		body:       a.request,
+		headers:    a.headers,
+		authorized: true,
+		idempotent: true,
+	})
 	if err != nil {
 		response.Data = map[string]any{"description": string(getListAggregationResponseInByte), "message": "Can't sent request", "error": err.Error()}
 		response.Status = "error"
@@ -0,0 +1,116 @@
+package ucodesdk
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// LimitScope selects which calls share the budget of a Limiter.
+type LimitScope int
+
+const (
+	// LimitPerClient applies one budget to every call.
+	LimitPerClient LimitScope = iota
+	// LimitPerCollection applies a budget per collection. Calls without a
+	// collection, like Auth calls, share one budget.
+	LimitPerCollection
+	// LimitPerOperation applies a budget per operation, e.g. one for
+	// OpItemsCreate and another for OpItemsGetList.
+	LimitPerOperation
+)
+
+// Limits configures a Limiter.
+type Limits struct {
+	// Rate is the number of calls allowed per second and Burst the number
+	// of calls that may be sent at once. Zero Rate disables rate limiting.
+	Rate  float64
+	Burst int
+	// MaxInFlight caps the number of calls waiting for a response. Zero
+	// means no cap.
+	MaxInFlight int
+	Per         LimitScope
+}
+
+// Limiter throttles calls on the client side with a token bucket and a cap
+// on the calls in flight. Calls over the limits wait for their turn until
+// their context is done. A Limiter may be shared by several clients, which
+// then share its budget.
+type Limiter struct {
+	limits Limits
+
+	mu      sync.Mutex
+	budgets map[string]*budget
+}
+
+type budget struct {
+	rate     *rate.Limiter
+	inFlight chan struct{}
+}
+
+// NewLimiter returns a limiter enforcing limits.
+func NewLimiter(limits Limits) *Limiter {
+	return &Limiter{limits: limits, budgets: map[string]*budget{}}
+}
+
+// wait blocks until call may be sent and records the time it waited in
+// call.Wait. The returned function must be called once call returned.
+func (l *Limiter) wait(ctx context.Context, call *Call) (func(), error) {
+	b := l.budget(call)
+	start := time.Now()
+	defer func() { call.Wait = time.Since(start) }()
+
+	if b.rate != nil {
+		reservation := b.rate.Reserve()
+		if delay := reservation.Delay(); delay > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				reservation.Cancel()
+				return nil, ctx.Err()
+			}
+		}
+	}
+
+	if b.inFlight == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case b.inFlight <- struct{}{}:
+		return func() { <-b.inFlight }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (l *Limiter) budget(call *Call) *budget {
+	var key string
+	switch l.limits.Per {
+	case LimitPerCollection:
+		key = call.Collection
+	case LimitPerOperation:
+		key = call.Operation
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.budgets[key]
+	if !ok {
+		b = &budget{}
+		if l.limits.Rate > 0 {
+			b.rate = rate.NewLimiter(rate.Limit(l.limits.Rate), max(l.limits.Burst, 1))
+		}
+		if l.limits.MaxInFlight > 0 {
+			b.inFlight = make(chan struct{}, l.limits.MaxInFlight)
+		}
+		l.budgets[key] = b
+	}
+
+	return b
+}
@@ -0,0 +1,117 @@
+package ucodesdk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			current := peak.Load()
+			if n <= current || peak.CompareAndSwap(current, n) {
+				break
+			}
+		}
+
+		if strings.Contains(r.URL.Path, "slow") {
+			<-release
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	t.Run("max in flight", func(t *testing.T) {
+		peak.Store(0)
+		ucodeApi := New(&Config{BaseURL: server.URL, Limiter: NewLimiter(Limits{MaxInFlight: 2})})
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _, err := ucodeApi.Items("houses").Create(map[string]any{}).Exec()
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		assert.LessOrEqual(t, peak.Load(), int32(2))
+	})
+
+	t.Run("rate", func(t *testing.T) {
+		var waits []time.Duration
+		record := func(next RoundTripFunc) RoundTripFunc {
+			return func(ctx context.Context, call *Call) (*Result, error) {
+				waits = append(waits, call.Wait)
+				return next(ctx, call)
+			}
+		}
+		ucodeApi := New(&Config{
+			BaseURL:    server.URL,
+			Limiter:    NewLimiter(Limits{Rate: 50, Burst: 1}),
+			Middleware: []Middleware{record},
+		})
+
+		start := time.Now()
+		for range 5 {
+			_, _, err := ucodeApi.Items("houses").GetList().Exec()
+			require.NoError(t, err)
+		}
+
+		assert.GreaterOrEqual(t, time.Since(start), 70*time.Millisecond)
+		require.Len(t, waits, 5)
+		assert.Greater(t, waits[4], 10*time.Millisecond)
+	})
+
+	t.Run("waits until the context is done", func(t *testing.T) {
+		ucodeApi := New(&Config{BaseURL: server.URL, Limiter: NewLimiter(Limits{Rate: 1, Burst: 1})})
+		_, _, err := ucodeApi.Items("houses").GetList().Exec()
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, _, err = ucodeApi.Items("houses").GetList().ExecContext(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+
+	t.Run("per collection", func(t *testing.T) {
+		ucodeApi := New(&Config{BaseURL: server.URL, Limiter: NewLimiter(Limits{MaxInFlight: 1, Per: LimitPerCollection})})
+
+		done := make(chan error)
+		go func() {
+			_, _, err := ucodeApi.Items("slow").GetList().Exec()
+			done <- err
+		}()
+		require.Eventually(t, func() bool { return inFlight.Load() == 1 }, time.Second, time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_, _, err := ucodeApi.Items("houses").GetList().ExecContext(ctx)
+		require.NoError(t, err)
+
+		ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, _, err = ucodeApi.Items("slow").GetList().ExecContext(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		close(release)
+		require.NoError(t, <-done)
+	})
+}
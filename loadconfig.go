@@ -0,0 +1,258 @@
+package ucodesdk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultBaseURL is used when a loaded configuration does not set BaseURL.
+// BaseAuthUrl has no default and must be set.
+const DefaultBaseURL = "https://api.client.u-code.io"
+
+// ProfileEnv is the environment variable selecting the profile of
+// ConfigFromEnv and LoadConfig.
+const ProfileEnv = "UCODE_PROFILE"
+
+// ErrInvalidConfig is returned by Config.Validate and the loaders when a
+// required field is missing or malformed.
+var ErrInvalidConfig = errors.New("ucode: invalid config")
+
+// Validate reports the missing or malformed fields of c. The returned error
+// matches ErrInvalidConfig.
+func (c *Config) Validate() error {
+	var errs []error
+
+	for _, field := range []struct{ name, value string }{
+		{"BaseURL", c.BaseURL},
+		{"BaseAuthUrl", c.BaseAuthUrl},
+	} {
+		if field.value == "" {
+			errs = append(errs, fmt.Errorf("%w: %s is required", ErrInvalidConfig, field.name))
+			continue
+		}
+		if u, err := url.Parse(field.value); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("%w: %s %q is not an absolute URL", ErrInvalidConfig, field.name, field.value))
+		}
+	}
+
+	if c.AppId == "" {
+		errs = append(errs, fmt.Errorf("%w: AppId is required", ErrInvalidConfig))
+	}
+	if c.ProjectId == "" {
+		errs = append(errs, fmt.Errorf("%w: ProjectId is required", ErrInvalidConfig))
+	}
+	if c.RequestTimeout < 0 {
+		errs = append(errs, fmt.Errorf("%w: RequestTimeout is negative", ErrInvalidConfig))
+	}
+
+	return errors.Join(errs...)
+}
+
+// ConfigFromEnv builds a Config from environment variables, using the
+// profile named by UCODE_PROFILE if set. See ConfigFromEnvProfile.
+func ConfigFromEnv() (*Config, error) {
+	return ConfigFromEnvProfile(os.Getenv(ProfileEnv))
+}
+
+// ConfigFromEnvProfile builds a Config from the variables UCODE_APP_ID,
+// UCODE_PROJECT_ID, UCODE_BASE_URL, UCODE_BASE_AUTH_URL, UCODE_FUNCTION_NAME,
+// UCODE_RESOURCE_ID, UCODE_ENVIRONMENT_ID and UCODE_REQUEST_TIMEOUT. With a
+// profile, e.g. "mongo", UCODE_MONGO_APP_ID and then MONGO_APP_ID take
+// precedence over UCODE_APP_ID, and likewise for every other variable.
+func ConfigFromEnvProfile(profile string) (*Config, error) {
+	return configFromVars(os.LookupEnv, profile)
+}
+
+// LoadConfig reads a Config from a JSON, YAML or .env file, chosen by its
+// extension, using the profile named by UCODE_PROFILE if set. See
+// LoadConfigProfile.
+func LoadConfig(path string) (*Config, error) {
+	return LoadConfigProfile(path, os.Getenv(ProfileEnv))
+}
+
+// LoadConfigProfile reads a Config from the file at path with the given
+// profile. JSON and YAML files hold the fields at the top level and
+// overrides per profile:
+//
+//	base_url: https://api.client.u-code.io
+//	project_id: 8f1c...
+//	default_profile: dev
+//	profiles:
+//	  dev:
+//	    app_id: P-dev...
+//	  prod:
+//	    app_id: P-prod...
+//	    request_timeout: 10s
+//
+// .env files use the variables of ConfigFromEnvProfile. An empty profile
+// selects default_profile, or UCODE_PROFILE in .env files.
+func LoadConfigProfile(path, profile string) (*Config, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == "" || ext == ".env" || strings.HasPrefix(filepath.Base(path), ".env") {
+		vars, err := godotenv.Read(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if profile == "" {
+			profile = vars[ProfileEnv]
+		}
+
+		return configFromVars(func(key string) (string, bool) {
+			value, ok := vars[key]
+			return value, ok
+		}, profile)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file configFile
+	switch ext {
+	case ".json":
+		err = json.Unmarshal(data, &file)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &file)
+	default:
+		return nil, fmt.Errorf("ucode: unsupported config file %q", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ucode: reading config %s: %w", path, err)
+	}
+
+	if profile == "" {
+		profile = file.DefaultProfile
+	}
+
+	values := file.configValues
+	if profile != "" {
+		overrides, ok := file.Profiles[profile]
+		if !ok {
+			return nil, fmt.Errorf("%w: profile %q not found in %s", ErrInvalidConfig, profile, path)
+		}
+		values = values.merge(overrides)
+	}
+
+	return values.config()
+}
+
+// configValues are the loadable fields of a Config.
+type configValues struct {
+	BaseURL        string            `json:"base_url" yaml:"base_url"`
+	BaseAuthUrl    string            `json:"base_auth_url" yaml:"base_auth_url"`
+	AppId          string            `json:"app_id" yaml:"app_id"`
+	ProjectId      string            `json:"project_id" yaml:"project_id"`
+	FunctionName   string            `json:"function_name" yaml:"function_name"`
+	ResourceId     string            `json:"resource_id" yaml:"resource_id"`
+	EnvironmentId  string            `json:"environment_id" yaml:"environment_id"`
+	RequestTimeout string            `json:"request_timeout" yaml:"request_timeout"`
+	Headers        map[string]string `json:"headers" yaml:"headers"`
+}
+
+type configFile struct {
+	configValues   `yaml:",inline"`
+	DefaultProfile string                  `json:"default_profile" yaml:"default_profile"`
+	Profiles       map[string]configValues `json:"profiles" yaml:"profiles"`
+}
+
+// merge returns v with the non-empty fields of overrides applied.
+func (v configValues) merge(overrides configValues) configValues {
+	for _, field := range []struct{ to, from *string }{
+		{&v.BaseURL, &overrides.BaseURL},
+		{&v.BaseAuthUrl, &overrides.BaseAuthUrl},
+		{&v.AppId, &overrides.AppId},
+		{&v.ProjectId, &overrides.ProjectId},
+		{&v.FunctionName, &overrides.FunctionName},
+		{&v.ResourceId, &overrides.ResourceId},
+		{&v.EnvironmentId, &overrides.EnvironmentId},
+		{&v.RequestTimeout, &overrides.RequestTimeout},
+	} {
+		if *field.from != "" {
+			*field.to = *field.from
+		}
+	}
+
+	if len(overrides.Headers) > 0 {
+		headers := make(map[string]string, len(v.Headers)+len(overrides.Headers))
+		for key, value := range v.Headers {
+			headers[key] = value
+		}
+		for key, value := range overrides.Headers {
+			headers[key] = value
+		}
+		v.Headers = headers
+	}
+
+	return v
+}
+
+// config applies the default base URLs and validates the result.
+func (v configValues) config() (*Config, error) {
+	cfg := &Config{
+		BaseURL:       v.BaseURL,
+		BaseAuthUrl:   v.BaseAuthUrl,
+		AppId:         v.AppId,
+		ProjectId:     v.ProjectId,
+		FunctionName:  v.FunctionName,
+		ResourceId:    v.ResourceId,
+		EnvironmentId: v.EnvironmentId,
+		Headers:       v.Headers,
+	}
+
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = DefaultBaseURL
+	}
+
+	if v.RequestTimeout != "" {
+		timeout, err := time.ParseDuration(v.RequestTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("%w: request timeout %q: %v", ErrInvalidConfig, v.RequestTimeout, err)
+		}
+		cfg.RequestTimeout = timeout
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func configFromVars(lookup func(string) (string, bool), profile string) (*Config, error) {
+	prefixes := []string{"UCODE_"}
+	if profile != "" {
+		upper := strings.ToUpper(profile)
+		prefixes = []string{"UCODE_" + upper + "_", upper + "_", "UCODE_"}
+	}
+
+	get := func(key string) string {
+		for _, prefix := range prefixes {
+			if value, ok := lookup(prefix + key); ok && value != "" {
+				return value
+			}
+		}
+		return ""
+	}
+
+	return configValues{
+		BaseURL:        get("BASE_URL"),
+		BaseAuthUrl:    get("BASE_AUTH_URL"),
+		AppId:          get("APP_ID"),
+		ProjectId:      get("PROJECT_ID"),
+		FunctionName:   get("FUNCTION_NAME"),
+		ResourceId:     get("RESOURCE_ID"),
+		EnvironmentId:  get("ENVIRONMENT_ID"),
+		RequestTimeout: get("REQUEST_TIMEOUT"),
+	}.config()
+}
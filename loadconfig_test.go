@@ -0,0 +1,128 @@
+package ucodesdk
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("UCODE_APP_ID", "P-default")
+	t.Setenv("UCODE_PROJECT_ID", "project-1")
+	t.Setenv("UCODE_BASE_AUTH_URL", "https://auth.example.com")
+	t.Setenv("UCODE_REQUEST_TIMEOUT", "5s")
+	t.Setenv("MONGO_APP_ID", "P-mongo")
+	t.Setenv("UCODE_POSTGRES_APP_ID", "P-postgres")
+	t.Setenv(ProfileEnv, "")
+
+	cfg, err := ConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "P-default", cfg.AppId)
+	assert.Equal(t, "project-1", cfg.ProjectId)
+	assert.Equal(t, DefaultBaseURL, cfg.BaseURL)
+	assert.Equal(t, "https://auth.example.com", cfg.BaseAuthUrl)
+	assert.Equal(t, 5*time.Second, cfg.RequestTimeout)
+
+	cfg, err = ConfigFromEnvProfile("mongo")
+	require.NoError(t, err)
+	assert.Equal(t, "P-mongo", cfg.AppId)
+	assert.Equal(t, "project-1", cfg.ProjectId)
+
+	t.Setenv(ProfileEnv, "postgres")
+	cfg, err = ConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "P-postgres", cfg.AppId)
+
+	t.Setenv("UCODE_PROJECT_ID", "")
+	_, err = ConfigFromEnv()
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	assert.ErrorContains(t, err, "ProjectId is required")
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv(ProfileEnv, "")
+	dir := t.TempDir()
+
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	yamlPath := write("ucode.yaml", `
+project_id: project-1
+base_auth_url: https://auth.example.com
+headers:
+  X-Team: billing
+default_profile: dev
+profiles:
+  dev:
+    app_id: P-dev
+  prod:
+    app_id: P-prod
+    base_url: https://api.example.com
+    request_timeout: 10s
+    headers:
+      X-Env: prod
+`)
+
+	t.Run("yaml default profile", func(t *testing.T) {
+		cfg, err := LoadConfig(yamlPath)
+		require.NoError(t, err)
+		assert.Equal(t, "P-dev", cfg.AppId)
+		assert.Equal(t, "project-1", cfg.ProjectId)
+		assert.Equal(t, DefaultBaseURL, cfg.BaseURL)
+		assert.Equal(t, map[string]string{"X-Team": "billing"}, cfg.Headers)
+	})
+
+	t.Run("yaml named profile", func(t *testing.T) {
+		t.Setenv(ProfileEnv, "prod")
+
+		cfg, err := LoadConfig(yamlPath)
+		require.NoError(t, err)
+		assert.Equal(t, "P-prod", cfg.AppId)
+		assert.Equal(t, "https://api.example.com", cfg.BaseURL)
+		assert.Equal(t, 10*time.Second, cfg.RequestTimeout)
+		assert.Equal(t, map[string]string{"X-Team": "billing", "X-Env": "prod"}, cfg.Headers)
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		_, err := LoadConfigProfile(yamlPath, "staging")
+		assert.ErrorIs(t, err, ErrInvalidConfig)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		path := write("ucode.json", `{"app_id": "P-json", "project_id": "project-2", "base_auth_url": "https://auth.example.com"}`)
+
+		cfg, err := LoadConfig(path)
+		require.NoError(t, err)
+		assert.Equal(t, "P-json", cfg.AppId)
+		assert.Equal(t, "https://auth.example.com", cfg.BaseAuthUrl)
+	})
+
+	t.Run("env file", func(t *testing.T) {
+		path := write(".env", "UCODE_PROJECT_ID=project-3\nUCODE_BASE_AUTH_URL=https://auth.example.com\nMONGO_APP_ID=P-mongo\nPOSTGRES_APP_ID=P-postgres\nUCODE_PROFILE=mongo\n")
+
+		cfg, err := LoadConfig(path)
+		require.NoError(t, err)
+		assert.Equal(t, "P-mongo", cfg.AppId)
+
+		cfg, err = LoadConfigProfile(path, "postgres")
+		require.NoError(t, err)
+		assert.Equal(t, "P-postgres", cfg.AppId)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		path := write("invalid.json", `{"base_url": "not a url", "request_timeout": "5s"}`)
+
+		_, err := LoadConfig(path)
+		assert.ErrorIs(t, err, ErrInvalidConfig)
+		assert.ErrorContains(t, err, "BaseURL")
+		assert.ErrorContains(t, err, "BaseAuthUrl is required")
+		assert.ErrorContains(t, err, "AppId is required")
+	})
+}
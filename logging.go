@@ -0,0 +1,199 @@
+package ucodesdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// redacted replaces secrets in logged headers, bodies and URLs.
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are the substrings of header names and body keys whose
+// values are never logged.
+var sensitiveKeys = []string{"password", "token", "secret", "otp", "api-key", "api_key", "apikey", "authorization", "cookie"}
+
+func sensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// logCalls logs every call sent by next to logger: a record at Info level
+// for successful calls and at Error level for failed ones. The headers and
+// bodies are added to the record when Debug is enabled.
+func logCalls(logger *slog.Logger, next RoundTripFunc) RoundTripFunc {
+	return func(ctx context.Context, call *Call) (*Result, error) {
+		start := time.Now()
+		result, err := next(ctx, call)
+
+		level := slog.LevelInfo
+		if err != nil {
+			level = slog.LevelError
+		}
+		if !logger.Enabled(ctx, level) {
+			return result, err
+		}
+
+		attrs := []slog.Attr{
+			slog.String("operation", call.Operation),
+			slog.String("collection", call.Collection),
+			slog.String("method", call.Method),
+			slog.String("url", redactURL(call)),
+			slog.Int("attempt", call.Attempt),
+			slog.Duration("latency", time.Since(start)),
+		}
+		if call.Wait > 0 {
+			attrs = append(attrs, slog.Duration("wait", call.Wait))
+		}
+		if result != nil {
+			attrs = append(attrs, slog.Int("status", result.StatusCode), slog.Int("response_size", len(result.Body)))
+		}
+		if err != nil {
+			attrs = append(attrs, slog.String("error", RedactError(call, err)))
+		}
+
+		if logger.Enabled(ctx, slog.LevelDebug) {
+			attrs = append(attrs, slog.Any("headers", redactHeaders(call.Headers)), slog.Any("body", redactBody(call.Body)))
+			if result != nil {
+				attrs = append(attrs, slog.Any("response", redactBody(result.Body)))
+			}
+		}
+
+		logger.LogAttrs(ctx, level, "ucode call", attrs...)
+
+		return result, err
+	}
+}
+
+func redactHeaders(headers map[string]string) map[string]string {
+	logged := make(map[string]string, len(headers))
+	for key, value := range headers {
+		if sensitive(key) {
+			value = redacted
+		}
+		logged[key] = value
+	}
+	return logged
+}
+
+// redactBody returns body decoded from JSON with the values of sensitive
+// keys replaced. Bodies that are not JSON, like file uploads, are reduced to
+// their size.
+func redactBody(body any) any {
+	data, ok := body.([]byte)
+	if !ok {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return nil
+		}
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return slog.GroupValue(slog.Int("size", len(data)))
+	}
+
+	return redactValue(value)
+}
+
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if sensitive(key) {
+				v[key] = redacted
+			} else {
+				v[key] = redactValue(item)
+			}
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
+
+// redactJSON returns value with the values of sensitive keys replaced when
+// it is a JSON object or array, unchanged otherwise.
+func redactJSON(value string) string {
+	trimmed := strings.TrimSpace(value)
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		return value
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(trimmed))
+	decoder.UseNumber()
+
+	var decoded any
+	if err := decoder.Decode(&decoded); err != nil || decoder.More() {
+		return value
+	}
+
+	encoded, err := json.Marshal(redactValue(decoded))
+	if err != nil {
+		return value
+	}
+
+	return string(encoded)
+}
+
+// RedactError returns the message of err, an error of call, with the URL of
+// call, which APIError and url.Error include, redacted as in the records of
+// Config.Logger: sensitive query parameters, sensitive keys of JSON query
+// parameters and the VerifyOTP code are replaced by [REDACTED].
+func RedactError(call *Call, err error) string {
+	message := err.Error()
+	logged := redactURL(call)
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) && urlErr.URL != "" {
+		message = strings.ReplaceAll(message, urlErr.URL, logged)
+	}
+
+	return strings.ReplaceAll(message, call.URL, logged)
+}
+
+// redactURL hides the sensitive query parameters of the URL of call, the
+// sensitive keys of its JSON query parameters and the code VerifyOTP sends
+// in its path, /verify/{sms_id}/{otp}.
+func redactURL(call *Call) string {
+	u, err := url.Parse(call.URL)
+	if err != nil {
+		return call.URL
+	}
+
+	if call.Operation == OpAuthVerifyOTP && path.Base(path.Dir(path.Dir(u.Path))) == "verify" {
+		u.Path = path.Join(path.Dir(u.Path), redacted)
+		u.RawPath = path.Join(path.Dir(u.EscapedPath()), redacted)
+	}
+
+	query := u.Query()
+	for key, values := range query {
+		if sensitive(key) {
+			query.Set(key, redacted)
+			continue
+		}
+		// GetList sends its filters as JSON in the data parameter.
+		for i, value := range values {
+			values[i] = redactJSON(value)
+		}
+	}
+	// Encode escapes the brackets of the redacted values.
+	u.RawQuery = strings.ReplaceAll(query.Encode(), url.QueryEscape(redacted), redacted)
+
+	return u.String()
+}
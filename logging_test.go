@@ -0,0 +1,176 @@
+package ucodesdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.HasPrefix(r.URL.Path, "/v2/items/missing") || bytes.Contains(body, []byte("987654")) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"data":{"token":{"access_token":"secret-access","refresh_token":"secret-refresh"},"user_id":"u1"}}`))
+	}))
+	defer server.Close()
+
+	records := func(t *testing.T, level slog.Level, call func(UcodeApis)) []map[string]any {
+		t.Helper()
+
+		var buf bytes.Buffer
+		ucodeApi := New(&Config{
+			BaseURL:     server.URL,
+			BaseAuthUrl: server.URL,
+			AppId:       "secret-app-key",
+			Logger:      slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: level})),
+			// Lists carry a token in their query to check it is redacted.
+			Middleware: []Middleware{func(next RoundTripFunc) RoundTripFunc {
+				return func(ctx context.Context, call *Call) (*Result, error) {
+					if call.Operation == OpItemsGetList {
+						call.URL += "&access_token=secret-access"
+					}
+					return next(ctx, call)
+				}
+			}},
+		})
+		call(ucodeApi)
+
+		for _, secret := range []string{"secret-app-key", "secret-access", "secret-refresh", "secret-password", "123456", "987654", "pw-filter"} {
+			assert.NotContains(t, buf.String(), secret)
+		}
+
+		var logged []map[string]any
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var record map[string]any
+			require.NoError(t, json.Unmarshal([]byte(line), &record))
+			logged = append(logged, record)
+		}
+		return logged
+	}
+
+	t.Run("info", func(t *testing.T) {
+		logged := records(t, slog.LevelInfo, func(ucodeApi UcodeApis) {
+			ucodeApi.Items("houses").GetList().Exec()
+			ucodeApi.Items("missing").GetSingle("1").Exec()
+		})
+		require.Len(t, logged, 2)
+
+		assert.Equal(t, "INFO", logged[0]["level"])
+		assert.Equal(t, OpItemsGetList, logged[0]["operation"])
+		assert.Equal(t, "houses", logged[0]["collection"])
+		assert.Equal(t, http.MethodGet, logged[0]["method"])
+		assert.Equal(t, float64(http.StatusOK), logged[0]["status"])
+		assert.Equal(t, float64(1), logged[0]["attempt"])
+		assert.Positive(t, logged[0]["response_size"])
+		assert.Contains(t, logged[0], "latency")
+		assert.NotContains(t, logged[0], "headers")
+
+		assert.Equal(t, "ERROR", logged[1]["level"])
+		assert.Equal(t, float64(http.StatusNotFound), logged[1]["status"])
+		assert.Contains(t, logged[1]["error"], "404")
+	})
+
+	t.Run("debug redacts secrets", func(t *testing.T) {
+		logged := records(t, slog.LevelDebug, func(ucodeApi UcodeApis) {
+			ucodeApi.Auth().Login(map[string]any{"username": "john", "password": "secret-password"}).Exec()
+			ucodeApi.Auth().VerifyOTP(VerifyOTPRequest{SmsId: "sms-1", Otp: "123456"}).Exec()
+			ucodeApi.Items("houses").GetList().Exec()
+		})
+		require.Len(t, logged, 3)
+
+		body := logged[0]["body"].(map[string]any)
+		assert.Equal(t, "john", body["username"])
+		assert.Equal(t, redacted, body["password"])
+		assert.Equal(t, redacted, logged[0]["response"].(map[string]any)["data"].(map[string]any)["token"])
+
+		assert.Equal(t, server.URL+"/v2/verify/sms-1/[REDACTED]?project-id=", logged[1]["url"])
+		assert.Equal(t, redacted, logged[1]["body"].(map[string]any)["otp"])
+
+		assert.Equal(t, server.URL+"/v2/items/houses?access_token=[REDACTED]&data=%7B%7D&from-ofs=true&limit=10&offset=0", logged[2]["url"])
+
+		headers := logged[2]["headers"].(map[string]any)
+		assert.Equal(t, redacted, headers["X-Api-Key"])
+		assert.Equal(t, redacted, headers["Authorization"])
+	})
+
+	t.Run("list filters", func(t *testing.T) {
+		logged := records(t, slog.LevelInfo, func(ucodeApi UcodeApis) {
+			ucodeApi.Items("houses").GetList().Filter(map[string]any{"password": "pw-filter", "rooms": 3}).Exec()
+			_, _, err := ucodeApi.Items("missing").GetList().Filter(map[string]any{"password": "pw-filter"}).Exec()
+			require.Error(t, err)
+		})
+		require.Len(t, logged, 2)
+
+		assert.Equal(t, server.URL+"/v2/items/houses?access_token=[REDACTED]&data=%7B%22password%22%3A%22[REDACTED]%22%2C%22rooms%22%3A3%7D&from-ofs=true&limit=10&offset=0", logged[0]["url"])
+		assert.Contains(t, logged[1]["error"], "%22password%22%3A%22[REDACTED]%22")
+	})
+
+	t.Run("failed verify otp", func(t *testing.T) {
+		logged := records(t, slog.LevelInfo, func(ucodeApi UcodeApis) {
+			_, _, err := ucodeApi.Auth().VerifyOTP(VerifyOTPRequest{SmsId: "sms-1", Otp: "987654"}).Exec()
+			require.Error(t, err)
+		})
+		require.Len(t, logged, 1)
+
+		assert.Equal(t, "ERROR", logged[0]["level"])
+		assert.Contains(t, logged[0]["error"], "/v2/verify/sms-1")
+		assert.Contains(t, logged[0]["error"], "404")
+	})
+}
+
+func TestRedactURL(t *testing.T) {
+	tests := []struct {
+		name string
+		call Call
+		url  string
+	}{
+		{
+			name: "sensitive query",
+			call: Call{Operation: OpItemsGetList, URL: "https://api/v2/items/houses?access_token=t1&limit=10"},
+			url:  "https://api/v2/items/houses?access_token=[REDACTED]&limit=10",
+		},
+		{
+			name: "sensitive json query",
+			call: Call{Operation: OpItemsGetList, URL: `https://api/v2/items/houses?data={"password":"pw","nested":[{"token":"t1"}],"n":12345678901234567}`},
+			url:  "https://api/v2/items/houses?data=%7B%22n%22%3A12345678901234567%2C%22nested%22%3A%5B%7B%22token%22%3A%22[REDACTED]%22%7D%5D%2C%22password%22%3A%22[REDACTED]%22%7D",
+		},
+		{
+			name: "plain query",
+			call: Call{Operation: OpItemsGetList, URL: "https://api/v2/items/houses?search={name&limit=10"},
+			url:  "https://api/v2/items/houses?limit=10&search=%7Bname",
+		},
+		{
+			name: "otp in path",
+			call: Call{Operation: OpAuthVerifyOTP, URL: "https://auth/v2/verify/sms-1/123456?project-id=p1"},
+			url:  "https://auth/v2/verify/sms-1/[REDACTED]?project-id=p1",
+		},
+		{
+			name: "otp in body",
+			call: Call{Operation: OpAuthVerifyOTP, URL: "https://auth/v2/verify/sms-1?project-id=p1"},
+			url:  "https://auth/v2/verify/sms-1?project-id=p1",
+		},
+		{
+			name: "other operations keep their path",
+			call: Call{Operation: OpItemsGetSingle, URL: "https://api/v2/items/verify/sms-1/123456"},
+			url:  "https://api/v2/items/verify/sms-1/123456",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.url, redactURL(&tt.call))
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package ucodesdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Login strategies of the /v2/login/with-option endpoint.
+const (
+	StrategyPhoneOTP   = "PHONE_OTP"
+	StrategyEmailOTP   = "EMAIL_OTP"
+	StrategyGoogleAuth = "GOOGLE_AUTH"
+)
+
+// LoginStrategy is a typed way of logging in, sent with Auth().LoginWith.
+// It is implemented by PasswordLogin, PhoneOTPLogin, EmailOTPLogin,
+// GoogleLogin and CustomLogin.
+type LoginStrategy interface {
+	// Validate reports a missing required field. The returned error
+	// matches ErrValidation.
+	Validate() error
+
+	loginRequest() loginRequest
+}
+
+// loginRequest is the body of a login and the endpoint it is sent to.
+type loginRequest struct {
+	withOption bool
+	body       map[string]any
+}
+
+// PasswordLogin logs in with a login and password through /v2/login.
+type PasswordLogin struct {
+	Username     string
+	Password     string
+	ClientTypeId string
+	RoleId       string
+}
+
+func (l PasswordLogin) Validate() error {
+	return required("password login", "username", l.Username, "password", l.Password)
+}
+
+func (l PasswordLogin) loginRequest() loginRequest {
+	body := map[string]any{"username": l.Username, "password": l.Password}
+	setIfNotEmpty(body, "client_type", l.ClientTypeId)
+	setIfNotEmpty(body, "role_id", l.RoleId)
+
+	return loginRequest{body: body}
+}
+
+// PhoneOTPLogin logs in with the code sent to Phone by SendCode, whose
+// response carries the SmsId.
+type PhoneOTPLogin struct {
+	Phone        string
+	SmsId        string
+	Otp          string
+	ClientTypeId string
+	RoleId       string
+}
+
+func (l PhoneOTPLogin) Validate() error {
+	return required("phone otp login", "phone", l.Phone, "sms id", l.SmsId, "otp", l.Otp)
+}
+
+func (l PhoneOTPLogin) loginRequest() loginRequest {
+	return withOption(StrategyPhoneOTP, map[string]any{"phone": l.Phone, "sms_id": l.SmsId, "otp": l.Otp}, l.ClientTypeId, l.RoleId)
+}
+
+// EmailOTPLogin logs in with the code sent to Email by SendCode, whose
+// response carries the SmsId.
+type EmailOTPLogin struct {
+	Email        string
+	SmsId        string
+	Otp          string
+	ClientTypeId string
+	RoleId       string
+}
+
+func (l EmailOTPLogin) Validate() error {
+	return required("email otp login", "email", l.Email, "sms id", l.SmsId, "otp", l.Otp)
+}
+
+func (l EmailOTPLogin) loginRequest() loginRequest {
+	return withOption(StrategyEmailOTP, map[string]any{"email": l.Email, "sms_id": l.SmsId, "otp": l.Otp}, l.ClientTypeId, l.RoleId)
+}
+
+// GoogleLogin logs in with the ID token returned by Google Sign-In.
+type GoogleLogin struct {
+	GoogleToken  string
+	ClientTypeId string
+	RoleId       string
+}
+
+func (l GoogleLogin) Validate() error {
+	return required("google login", "google token", l.GoogleToken)
+}
+
+func (l GoogleLogin) loginRequest() loginRequest {
+	return withOption(StrategyGoogleAuth, map[string]any{"google_token": l.GoogleToken}, l.ClientTypeId, l.RoleId)
+}
+
+// CustomLogin sends Data with any other strategy of /v2/login/with-option,
+// e.g. a third-party provider configured for the project.
+type CustomLogin struct {
+	Strategy string
+	Data     map[string]any
+}
+
+func (l CustomLogin) Validate() error {
+	return required("custom login", "strategy", l.Strategy)
+}
+
+func (l CustomLogin) loginRequest() loginRequest {
+	data := make(map[string]any, len(l.Data))
+	for key, value := range l.Data {
+		data[key] = value
+	}
+
+	return loginRequest{withOption: true, body: map[string]any{"login_strategy": l.Strategy, "data": data}}
+}
+
+func withOption(strategy string, data map[string]any, clientTypeId, roleId string) loginRequest {
+	setIfNotEmpty(data, "client_type_id", clientTypeId)
+	setIfNotEmpty(data, "role_id", roleId)
+
+	return loginRequest{withOption: true, body: map[string]any{"login_strategy": strategy, "data": data}}
+}
+
+func setIfNotEmpty(body map[string]any, key, value string) {
+	if value != "" {
+		body[key] = value
+	}
+}
+
+// required returns an ErrValidation error naming the first empty field of
+// the name, value pairs.
+func required(strategy string, fields ...string) error {
+	for i := 0; i+1 < len(fields); i += 2 {
+		if fields[i+1] == "" {
+			return fmt.Errorf("%w: %s requires %s", ErrValidation, strategy, fields[i])
+		}
+	}
+	return nil
+}
+
+func (a *APIAuth) LoginWith(strategy LoginStrategy) *LoginWith {
+	return &LoginWith{
+		sdk:      a.sdk,
+		strategy: strategy,
+	}
+}
+
+func (a *LoginWith) Headers(headers map[string]string) *LoginWith {
+	a.headers = headers
+	return a
+}
+
+func (a *LoginWith) Exec() (LoginResponse, Response, error) {
+	return a.ExecContext(context.Background())
+}
+
+func (a *LoginWith) ExecContext(ctx context.Context) (LoginResponse, Response, error) {
+	var (
+		response    = Response{Status: "done"}
+		loginObject LoginResponse
+	)
+
+	if err := a.strategy.Validate(); err != nil {
+		response.Data = map[string]any{"message": "Invalid login", "error": err.Error()}
+		response.Status = "error"
+		return LoginResponse{}, response, err
+	}
+
+	request := a.strategy.loginRequest()
+
+	url, operation := fmt.Sprintf("%s/v2/login", a.sdk.config.BaseAuthUrl), OpAuthLogin
+	if request.withOption {
+		url = fmt.Sprintf("%s/v2/login/with-option?project-id=%s", a.sdk.config.BaseAuthUrl, a.sdk.config.ProjectId)
+		operation = OpAuthLoginWithOption
+	} else {
+		request.body["project_id"] = a.sdk.config.ProjectId
+	}
+
+	loginResponseInByte, err := a.sdk.send(ctx, apiCall{
+		operation: operation,
+		url:       url,
+		method:    http.MethodPost,
+		body:      request.body,
+		headers:   a.headers,
+	})
+	if err != nil {
+		response.Data = map[string]any{"description": string(loginResponseInByte), "message": "Can't send request", "error": err.Error()}
+		response.Status = "error"
+		return LoginResponse{}, response, err
+	}
+
+	err = json.Unmarshal(loginResponseInByte, &loginObject)
+	if err != nil {
+		response.Data = map[string]any{"description": string(loginResponseInByte), "message": "Error while unmarshalling login object", "error": err.Error()}
+		response.Status = "error"
+		return LoginResponse{}, response, err
+	}
+
+	return loginObject, response, nil
+}
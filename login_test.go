@@ -0,0 +1,96 @@
+package ucodesdk
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginWith(t *testing.T) {
+	var (
+		path string
+		body map[string]any
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"data":{"user_id":"user-1","token":{"access_token":"token"}}}`))
+	}))
+	defer server.Close()
+
+	sdk := New(&Config{BaseAuthUrl: server.URL, ProjectId: "project-1"})
+
+	tests := []struct {
+		name     string
+		strategy LoginStrategy
+		path     string
+		body     string
+	}{
+		{
+			name:     "password",
+			strategy: PasswordLogin{Username: "john", Password: "secret", ClientTypeId: "ct-1"},
+			path:     "/v2/login",
+			body:     `{"username":"john","password":"secret","client_type":"ct-1","project_id":"project-1"}`,
+		},
+		{
+			name:     "phone otp",
+			strategy: PhoneOTPLogin{Phone: "+998900000000", SmsId: "sms-1", Otp: "111111", ClientTypeId: "ct-1", RoleId: "role-1"},
+			path:     "/v2/login/with-option",
+			body:     `{"login_strategy":"PHONE_OTP","data":{"phone":"+998900000000","sms_id":"sms-1","otp":"111111","client_type_id":"ct-1","role_id":"role-1"}}`,
+		},
+		{
+			name:     "email otp",
+			strategy: EmailOTPLogin{Email: "john@example.com", SmsId: "sms-1", Otp: "111111"},
+			path:     "/v2/login/with-option",
+			body:     `{"login_strategy":"EMAIL_OTP","data":{"email":"john@example.com","sms_id":"sms-1","otp":"111111"}}`,
+		},
+		{
+			name:     "google",
+			strategy: GoogleLogin{GoogleToken: "id-token"},
+			path:     "/v2/login/with-option",
+			body:     `{"login_strategy":"GOOGLE_AUTH","data":{"google_token":"id-token"}}`,
+		},
+		{
+			name:     "custom",
+			strategy: CustomLogin{Strategy: "APPLE_AUTH", Data: map[string]any{"apple_code": "code"}},
+			path:     "/v2/login/with-option",
+			body:     `{"login_strategy":"APPLE_AUTH","data":{"apple_code":"code"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			login, _, err := sdk.Auth().LoginWith(tt.strategy).Exec()
+			require.NoError(t, err)
+			assert.Equal(t, "token", login.Data.Token.AccessToken)
+			assert.Equal(t, tt.path, path)
+
+			data, _ := json.Marshal(body)
+			assert.JSONEq(t, tt.body, string(data))
+		})
+	}
+}
+
+func TestLoginStrategyValidate(t *testing.T) {
+	for _, strategy := range []LoginStrategy{
+		PasswordLogin{Username: "john"},
+		PhoneOTPLogin{Phone: "+998900000000", Otp: "111111"},
+		EmailOTPLogin{SmsId: "sms-1", Otp: "111111"},
+		GoogleLogin{},
+		CustomLogin{Data: map[string]any{"code": "1"}},
+	} {
+		err := strategy.Validate()
+		assert.ErrorIs(t, err, ErrValidation, "%T", strategy)
+
+		_, response, err := New(&Config{}).Auth().LoginWith(strategy).Exec()
+		assert.ErrorIs(t, err, ErrValidation)
+		assert.Equal(t, "error", response.Status)
+	}
+
+	assert.EqualError(t, PhoneOTPLogin{Phone: "+998900000000", Otp: "111111"}.Validate(), "ucode: validation failed: phone otp login requires sms id")
+}
@@ -0,0 +1,161 @@
+package ucodesdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Operation names of the calls seen by middleware.
+const (
+	OpItemsCreate         = "items_create"
+	OpItemsUpdate         = "items_update"
+	OpItemsUpdateMultiple = "items_update_multiple"
+	OpItemsDelete         = "items_delete"
+	OpItemsDeleteMultiple = "items_delete_multiple"
+	OpItemsGetSingle      = "items_get_single"
+	OpItemsGetList        = "items_get_list"
+	OpItemsAggregation    = "items_aggregation"
+
+	OpAuthRegister        = "auth_register"
+	OpAuthResetPassword   = "auth_reset_password"
+	OpAuthLogin           = "auth_login"
+	OpAuthLoginWithOption = "auth_login_with_option"
+	OpAuthSendCode        = "auth_send_code"
+	OpAuthRefreshToken    = "auth_refresh_token"
+	OpAuthLogout          = "auth_logout"
+	OpAuthChangePassword  = "auth_change_password"
+	OpAuthVerifyOTP       = "auth_verify_otp"
+	OpAuthListSessions    = "auth_list_sessions"
+	OpAuthDeleteSession   = "auth_delete_session"
+	OpAuthHasAccess       = "auth_has_access"
+
+	OpFilesUpload = "files_upload"
+	OpFilesDelete = "files_delete"
+
+	OpFunctionInvoke = "function_invoke"
+)
+
+// Call is one attempt of a request to the u-code API as seen by middleware,
+// or the whole request as seen by Config.ExecMiddleware. Middleware may
+// change it before passing it on, e.g. to add headers.
+type Call struct {
+	// Operation is one of the Op constants.
+	Operation string
+	// Collection is the table slug of Items calls and the function path of
+	// Function calls.
+	Collection string
+	Method     string
+	URL        string
+	// Headers holds every header of the request, credentials included.
+	Headers map[string]string
+	// Body is encoded as JSON. File uploads carry the multipart form as a
+	// []byte, which is sent as is.
+	Body any
+	// Attempt is 1 for the first attempt and grows with every retry. It is
+	// 0 in ExecMiddleware.
+	Attempt int
+	// Wait is the time the call waited for Config.Limiter before it was
+	// passed to the middleware.
+	Wait time.Duration
+}
+
+// Result is the response to a Call. It is returned along with an *APIError
+// for responses with a status of 400 or above, and is nil when no response
+// was received.
+type Result struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Decode decodes the JSON body of r into v.
+func (r *Result) Decode(v any) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// RoundTripFunc sends a Call and returns its Result.
+type RoundTripFunc func(ctx context.Context, call *Call) (*Result, error)
+
+// Middleware wraps the RoundTripFunc of every Items, Auth, Files and
+// Function call, e.g. to log, sign or measure them:
+//
+//	func logCalls(next ucodesdk.RoundTripFunc) ucodesdk.RoundTripFunc {
+//		return func(ctx context.Context, call *ucodesdk.Call) (*ucodesdk.Result, error) {
+//			result, err := next(ctx, call)
+//			log.Println(call.Operation, call.Collection, err)
+//			return result, err
+//		}
+//	}
+//
+// Middleware runs once per attempt, so retried calls pass through it again,
+// unless it is set on Config.ExecMiddleware.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// roundTrip sends call through the middleware of the Config once the
+// limiter lets it through. The logger sits closest to the wire so it records
+// the calls as they are sent.
+func (a *object) roundTrip(ctx context.Context, call *Call) (*Result, error) {
+	var next RoundTripFunc = func(ctx context.Context, call *Call) (*Result, error) {
+		return roundTrip(ctx, a.client, call)
+	}
+	if a.config.Logger != nil {
+		next = logCalls(a.config.Logger, next)
+	}
+
+	for i := len(a.config.Middleware) - 1; i >= 0; i-- {
+		next = a.config.Middleware[i](next)
+	}
+
+	if a.config.Limiter != nil {
+		release, err := a.config.Limiter.wait(ctx, call)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+
+	return next(ctx, call)
+}
+
+// roundTrip sends call with client.
+func roundTrip(ctx context.Context, client *http.Client, call *Call) (*Result, error) {
+	data, ok := call.Body.([]byte)
+	if !ok {
+		var err error
+		data, err = json.Marshal(&call.Body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	request, err := http.NewRequestWithContext(ctx, call.Method, call.URL, bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range call.Headers {
+		request.Header.Add(key, value)
+	}
+
+	resp, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	result := &Result{StatusCode: resp.StatusCode, Header: resp.Header}
+	result.Body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return result, err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return result, newAPIError(call.Method, call.URL, resp.StatusCode, resp.Header, result.Body)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,177 @@
+package ucodesdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		w.Write([]byte(`{"status":"OK","data":{"count":1}}`))
+	}))
+	defer server.Close()
+
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(ctx context.Context, call *Call) (*Result, error) {
+				order = append(order, name+" before")
+				result, err := next(ctx, call)
+				order = append(order, name+" after")
+				return result, err
+			}
+		}
+	}
+
+	var calls []*Call
+	var results []*Result
+	record := func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, call *Call) (*Result, error) {
+			call.Headers["X-Signature"] = call.Method + " " + call.URL
+			result, err := next(ctx, call)
+			calls = append(calls, call)
+			results = append(results, result)
+			return result, err
+		}
+	}
+
+	ucodeApi := New(&Config{
+		BaseURL:     server.URL,
+		BaseAuthUrl: server.URL,
+		AppId:       "app",
+		Middleware:  []Middleware{trace("outer"), trace("inner"), record},
+	})
+
+	t.Run("wraps calls in order", func(t *testing.T) {
+		_, _, err := ucodeApi.Items("houses").GetList().Exec()
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"outer before", "inner before", "inner after", "outer after"}, order)
+		assert.Equal(t, "GET "+calls[0].URL, headers.Get("X-Signature"))
+		assert.Equal(t, "app", calls[0].Headers["X-Api-Key"])
+
+		var body struct {
+			Data struct{ Count int } `json:"data"`
+		}
+		require.NoError(t, results[0].Decode(&body))
+		assert.Equal(t, 1, body.Data.Count)
+		assert.Equal(t, http.StatusOK, results[0].StatusCode)
+	})
+
+	t.Run("operations", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "photo.txt")
+		require.NoError(t, os.WriteFile(filePath, []byte("photo"), 0o600))
+
+		calls = nil
+		_, _, err := ucodeApi.Items("houses").Create(map[string]any{"name": "a"}).Exec()
+		require.NoError(t, err)
+		_, err = ucodeApi.Items("houses").Delete().Multiple([]string{"1"}).Exec()
+		require.NoError(t, err)
+		_, _, err = ucodeApi.Function("notify").Invoke(nil).Exec()
+		require.NoError(t, err)
+		_, _, err = ucodeApi.Auth().Login(map[string]any{"username": "john"}).Exec()
+		require.NoError(t, err)
+		_, _, err = ucodeApi.Files().Upload(filePath).Exec()
+		require.NoError(t, err)
+
+		var got [][2]string
+		for _, call := range calls {
+			got = append(got, [2]string{call.Operation, call.Collection})
+		}
+		assert.Equal(t, [][2]string{
+			{OpItemsCreate, "houses"},
+			{OpItemsDeleteMultiple, "houses"},
+			{OpFunctionInvoke, "notify"},
+			{OpAuthLogin, ""},
+			{OpFilesUpload, ""},
+		}, got)
+
+		upload := calls[len(calls)-1]
+		assert.True(t, strings.HasPrefix(headers.Get("Content-Type"), "multipart/form-data"))
+		assert.Contains(t, string(upload.Body.([]byte)), "photo")
+	})
+}
+
+func TestMiddlewareRetry(t *testing.T) {
+	server, requests := newFlakyServer(t, 0, http.StatusOK, nil)
+
+	var attempts []int
+	faulty := func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, call *Call) (*Result, error) {
+			attempts = append(attempts, call.Attempt)
+			if call.Attempt == 1 {
+				return &Result{StatusCode: http.StatusServiceUnavailable}, newAPIError(call.Method, call.URL, http.StatusServiceUnavailable, nil, nil)
+			}
+			return next(ctx, call)
+		}
+	}
+
+	ucodeApi := New(&Config{BaseURL: server.URL, Retry: testRetryPolicy(), Middleware: []Middleware{faulty}})
+
+	_, _, err := ucodeApi.Items("houses").GetList().Exec()
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, attempts)
+	assert.Equal(t, int32(1), requests.Load())
+
+	errBlocked := errors.New("blocked")
+	ucodeApi = New(&Config{BaseURL: server.URL, Middleware: []Middleware{func(RoundTripFunc) RoundTripFunc {
+		return func(context.Context, *Call) (*Result, error) { return nil, errBlocked }
+	}}})
+
+	_, response, err := ucodeApi.Items("houses").Create(map[string]any{}).Exec()
+	assert.ErrorIs(t, err, errBlocked)
+	assert.Equal(t, "error", response.Status)
+}
+
+func TestExecMiddleware(t *testing.T) {
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var execs, attempts []int
+	exec := func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, call *Call) (*Result, error) {
+			execs = append(execs, call.Attempt)
+			call.Headers = map[string]string{"X-Request-Id": "request-1"}
+			result, err := next(ctx, call)
+			assert.Equal(t, http.StatusOK, result.StatusCode)
+			return result, err
+		}
+	}
+	faulty := func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, call *Call) (*Result, error) {
+			attempts = append(attempts, call.Attempt)
+			if call.Attempt == 1 {
+				return &Result{StatusCode: http.StatusServiceUnavailable}, newAPIError(call.Method, call.URL, http.StatusServiceUnavailable, nil, nil)
+			}
+			return next(ctx, call)
+		}
+	}
+
+	ucodeApi := New(&Config{
+		BaseURL:        server.URL,
+		Retry:          testRetryPolicy(),
+		Middleware:     []Middleware{faulty},
+		ExecMiddleware: []Middleware{exec},
+	})
+
+	_, _, err := ucodeApi.Items("houses").GetList().Exec()
+	require.NoError(t, err)
+	assert.Equal(t, []int{0}, execs)
+	assert.Equal(t, []int{1, 2}, attempts)
+	assert.Equal(t, "request-1", headers.Get("X-Request-Id"))
+}
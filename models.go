@@ -73,6 +73,7 @@ type (
 
 	GetListClientApiResp struct {
 		Response []map[string]any `json:"response"`
+		Count    int              `json:"count"`
 	}
 	// GetListAggregationClientApiResponse  This is get list aggregation response
 	GetListAggregationClientApiResponse struct {
@@ -124,97 +125,161 @@ type AuthRequest struct {
 
 type APIItem struct {
 	collection string
-	config     *Config
+	sdk        *object
 }
 
 type CreateItem struct {
-	collection string
-	config     *Config
-	data       ActionBody
+	collection     string
+	sdk            *object
+	data           ActionBody
+	idempotencyKey string
+	headers        map[string]string
 }
 
 type DeleteItem struct {
 	collection  string
-	config      *Config
+	sdk         *object
 	disableFaas bool
 	id          string
+	headers     map[string]string
 }
 
 type DeleteMultipleItem struct {
 	collection  string
-	config      *Config
+	sdk         *object
 	disableFaas bool
 	ids         []string
+	headers     map[string]string
 }
 
 type UpdateItem struct {
-	collection string
-	config     *Config
-	data       ActionBody
+	collection     string
+	sdk            *object
+	data           ActionBody
+	idempotencyKey string
+	headers        map[string]string
 }
 
 type GetSingleItem struct {
 	collection string
-	config     *Config
+	sdk        *object
 	guid       string
+	headers    map[string]string
 }
 
 type GetListItem struct {
 	collection string
-	config     *Config
+	sdk        *object
 	request    Request
 	limit      int
 	page       int
+	maxItems   int
+	// offset, when positive, replaces the offset derived from page. All
+	// sets it to walk servers capping the page size.
+	offset  int
+	headers map[string]string
 }
 
 type GetListAggregation struct {
 	collection string
-	config     *Config
+	sdk        *object
 	request    Request
+	headers    map[string]string
 }
 
 type Register struct {
-	config *Config
-	data   AuthRequest
+	sdk  *object
+	data AuthRequest
 }
 
 type ResetPassword struct {
-	config *Config
-	data   AuthRequest
+	sdk  *object
+	data AuthRequest
 }
 
 type Login struct {
-	config *Config
-	data   AuthRequest
+	sdk  *object
+	data AuthRequest
 }
 
 type SendCode struct {
-	config *Config
-	data   AuthRequest
+	sdk  *object
+	data AuthRequest
+}
+
+type RefreshToken struct {
+	sdk          *object
+	refreshToken string
+	headers      map[string]string
+}
+
+type LoginWith struct {
+	sdk      *object
+	strategy LoginStrategy
+	headers  map[string]string
+}
+
+type Logout struct {
+	sdk     *object
+	request LogoutRequest
+	headers map[string]string
+}
+
+type ChangePassword struct {
+	sdk     *object
+	request ChangePasswordRequest
+	headers map[string]string
+}
+
+type VerifyOTP struct {
+	sdk     *object
+	request VerifyOTPRequest
+	headers map[string]string
+}
+
+type HasAccess struct {
+	sdk         *object
+	accessToken string
+	headers     map[string]string
+}
+
+type ListSessions struct {
+	sdk     *object
+	request ListSessionsRequest
+	headers map[string]string
+}
+
+type DeleteSession struct {
+	sdk     *object
+	id      string
+	headers map[string]string
 }
 
 type APIAuth struct {
-	config *Config
+	sdk *object
 }
 
 type APIFiles struct {
-	config *Config
+	sdk *object
 }
 
 type UploadFile struct {
-	config *Config
-	path   string
+	sdk     *object
+	path    string
+	headers map[string]string
 }
 
 type DeleteFile struct {
-	config *Config
-	id     string
+	sdk     *object
+	id      string
+	headers map[string]string
 }
 
 type APIFunction struct {
-	config  *Config
+	sdk     *object
 	request Request
 	path    string
+	headers map[string]string
 }
 
 type User struct {
@@ -294,6 +359,7 @@ type Session struct {
 	RoleId       string `json:"role_id"`
 	CreatedAt    string `json:"created_at"`
 	UpdatedAt    string `json:"updated_at"`
+	ExpiresAt    string `json:"expires_at"`
 	UserIdAuth   string `json:"user_id_auth"`
 }
 
@@ -328,3 +394,58 @@ type SendCodeResponse struct {
 		UserFound   bool   `json:"user_found"`
 	} `json:"data"`
 }
+
+type RefreshTokenResponse struct {
+	Status      string `json:"status"`
+	Description string `json:"description"`
+	Data        struct {
+		UserId string `json:"user_id"`
+		Token  *Token `json:"token"`
+	} `json:"data"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type ChangePasswordRequest struct {
+	UserId      string `json:"user_id"`
+	OldPassword string `json:"old_password"`
+	NewPassword string `json:"new_password"`
+}
+
+type VerifyOTPRequest struct {
+	SmsId string `json:"sms_id"`
+	Otp   string `json:"otp"`
+	// Data is sent along with the code, e.g. the fields of the user to
+	// register when no user was found by SendCode.
+	Data map[string]any `json:"data,omitempty"`
+}
+
+type VerifyOTPResponse struct {
+	Status      string `json:"status"`
+	Description string `json:"description"`
+	Data        struct {
+		UserFound bool           `json:"user_found"`
+		UserId    string         `json:"user_id"`
+		Token     *Token         `json:"token"`
+		Sessions  []*Session     `json:"sessions"`
+		UserData  map[string]any `json:"user_data"`
+	} `json:"data"`
+}
+
+type ListSessionsRequest struct {
+	UserId string
+	// Limit and Offset page the sessions; they are not sent when zero.
+	Limit  int
+	Offset int
+}
+
+type ListSessionsResponse struct {
+	Status      string `json:"status"`
+	Description string `json:"description"`
+	Data        struct {
+		Sessions []*Session `json:"sessions"`
+		Count    int        `json:"count"`
+	} `json:"data"`
+}
@@ -0,0 +1,114 @@
+package ucodesdk
+
+import (
+	"context"
+	"iter"
+	"maps"
+)
+
+// defaultPageSize is the page size used by All when Limit was not set.
+const defaultPageSize = 100
+
+// MaxItems caps the number of items yielded by All. Zero means no cap.
+func (a *GetListItem) MaxItems(maxItems int) *GetListItem {
+	a.maxItems = maxItems
+	return a
+}
+
+/*
+All walks every page of the list lazily, starting at Page (default 1) and
+fetching Limit items per request (default 100), or fewer when the server
+caps the page size. Filters, sort, search and
+view fields set on the builder apply to every page. Iteration stops after
+the last page, after MaxItems items or at the first error, which is yielded
+with a nil item.
+
+	for object, err := range sdk.Items("houses").GetList().Limit(200).All(ctx) {
+		if err != nil {
+			return err
+		}
+		...
+	}
+*/
+func (a *GetListItem) All(ctx context.Context) iter.Seq2[map[string]any, error] {
+	return func(yield func(map[string]any, error) bool) {
+		var (
+			limit   = a.limit
+			yielded = 0
+		)
+		if limit <= 0 {
+			limit = defaultPageSize
+		}
+		offset := (max(a.page, 1) - 1) * limit
+
+		for {
+			resp, _, err := a.pageRequest(offset, limit).ExecContext(ctx)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			objects := resp.Data.Data.Response
+			for _, object := range objects {
+				if !yield(object, nil) {
+					return
+				}
+
+				yielded++
+				if a.maxItems > 0 && yielded >= a.maxItems {
+					return
+				}
+			}
+
+			// The server may return fewer items than limit, so the next page
+			// starts after the items received. The list ends with an empty
+			// page or once count items were read; responses without a count
+			// end with a short page.
+			offset += len(objects)
+			count := resp.Data.Data.Count
+			if len(objects) == 0 || (count > 0 && offset >= count) || (count == 0 && len(objects) < limit) {
+				return
+			}
+		}
+	}
+}
+
+// pageRequest returns a copy of the builder positioned on offset, so that
+// walking the pages leaves the original builder untouched.
+func (a *GetListItem) pageRequest(offset, limit int) *GetListItem {
+	data := maps.Clone(a.request.Data)
+	data["offset"] = offset
+	data["limit"] = limit
+
+	return &GetListItem{
+		collection: a.collection,
+		sdk:        a.sdk,
+		request:    Request{Data: data, IsCached: a.request.IsCached},
+		limit:      limit,
+		page:       1,
+		offset:     offset,
+		headers:    a.headers,
+	}
+}
+
+// All walks every item of the collection like GetListItem.All, decoding
+// each one into T. Options configure the underlying builder.
+func (c *TypedCollection[T]) All(ctx context.Context, options ...func(*GetListItem)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		list := c.Items().GetList()
+		for _, option := range options {
+			option(list)
+		}
+
+		for object, err := range list.All(ctx) {
+			var item T
+			if err == nil {
+				err = c.decode(object, &item)
+			}
+
+			if !yield(item, err) || err != nil {
+				return
+			}
+		}
+	}
+}
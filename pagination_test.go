@@ -0,0 +1,152 @@
+package ucodesdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newListServer(t *testing.T, total int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		var data map[string]any
+		if err := json.Unmarshal([]byte(r.URL.Query().Get("data")), &data); err != nil || data["name"] != "house" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		objects := []map[string]any{}
+		for i := offset; i < min(offset+limit, total); i++ {
+			objects = append(objects, map[string]any{"guid": fmt.Sprint(i), "name": "house", "room_count": i})
+		}
+
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"data": map[string]any{"response": objects}}})
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func TestGetListAll(t *testing.T) {
+	ctx := context.Background()
+	server, requests := newListServer(t, 25)
+	ucodeApi := New(&Config{BaseURL: server.URL})
+
+	t.Run("walks every page", func(t *testing.T) {
+		requests.Store(0)
+
+		var guids []string
+		for object, err := range ucodeApi.Items("houses").GetList().Filter(map[string]any{"name": "house"}).Limit(10).All(ctx) {
+			assert.NoError(t, err)
+			guids = append(guids, object["guid"].(string))
+		}
+
+		assert.Len(t, guids, 25)
+		assert.Equal(t, "24", guids[24])
+		assert.Equal(t, int32(3), requests.Load())
+	})
+
+	t.Run("stops on exact last page", func(t *testing.T) {
+		server, requests := newListServer(t, 20)
+		ucodeApi := New(&Config{BaseURL: server.URL})
+
+		count := 0
+		for _, err := range ucodeApi.Items("houses").GetList().Filter(map[string]any{"name": "house"}).Limit(10).All(ctx) {
+			assert.NoError(t, err)
+			count++
+		}
+
+		assert.Equal(t, 20, count)
+		assert.Equal(t, int32(3), requests.Load())
+	})
+
+	t.Run("server caps the page size", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			limit = min(limit, 4)
+
+			objects := []map[string]any{}
+			for i := offset; i < min(offset+limit, 25); i++ {
+				objects = append(objects, map[string]any{"guid": fmt.Sprint(i)})
+			}
+
+			json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"data": map[string]any{"count": 25, "response": objects}}})
+		}))
+		defer server.Close()
+
+		var guids []string
+		for object, err := range New(&Config{BaseURL: server.URL}).Items("houses").GetList().Limit(10).All(ctx) {
+			assert.NoError(t, err)
+			guids = append(guids, object["guid"].(string))
+		}
+
+		assert.Len(t, guids, 25)
+		assert.Equal(t, "24", guids[24])
+		assert.Equal(t, int32(7), requests.Load())
+	})
+
+	t.Run("max items", func(t *testing.T) {
+		requests.Store(0)
+
+		count := 0
+		for _, err := range ucodeApi.Items("houses").GetList().Filter(map[string]any{"name": "house"}).Limit(10).MaxItems(15).All(ctx) {
+			assert.NoError(t, err)
+			count++
+		}
+
+		assert.Equal(t, 15, count)
+		assert.Equal(t, int32(2), requests.Load())
+	})
+
+	t.Run("break", func(t *testing.T) {
+		requests.Store(0)
+
+		for range ucodeApi.Items("houses").GetList().Filter(map[string]any{"name": "house"}).Limit(10).All(ctx) {
+			break
+		}
+
+		assert.Equal(t, int32(1), requests.Load())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		var errs []error
+		for object, err := range ucodeApi.Items("houses").GetList().All(ctx) {
+			assert.Nil(t, object)
+			errs = append(errs, err)
+		}
+
+		if assert.Len(t, errs, 1) {
+			assert.ErrorIs(t, errs[0], ErrBadRequest)
+		}
+	})
+
+	t.Run("typed", func(t *testing.T) {
+		houses := Collection[testHouse](ucodeApi, "houses")
+
+		var rooms int
+		for house, err := range houses.All(ctx, func(l *GetListItem) { l.Filter(map[string]any{"name": "house"}) }) {
+			assert.NoError(t, err)
+			rooms += house.RoomCount
+		}
+
+		assert.Equal(t, 300, rooms)
+	})
+}
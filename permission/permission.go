@@ -0,0 +1,254 @@
+/*
+Package permission evaluates the permissions u-code returns on login, so a
+function can enforce the rules of a user's role locally before issuing
+Items calls on their behalf.
+
+	perms, err := permission.FromLogin(login)
+	if !perms.Can("orders", permission.Update) {
+		return function.NewError(http.StatusForbidden, "You can't update orders", nil)
+	}
+	if err := perms.CheckWrite("orders", permission.Update, event.ObjectData); err != nil {
+		return err
+	}
+*/
+package permission
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	ucodesdk "github.com/ucode-io/ucode_sdk"
+)
+
+// Action is an operation on the items of a table.
+type Action string
+
+const (
+	Read   Action = "read"
+	Write  Action = "write"
+	Update Action = "update"
+	Delete Action = "delete"
+)
+
+// Grant is a permission flag. u-code sends them as "Yes"/"No" strings or as
+// booleans; both decode to a Grant.
+type Grant bool
+
+func (g *Grant) UnmarshalJSON(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case bool:
+		*g = Grant(v)
+	case string:
+		*g = Grant(strings.EqualFold(v, "yes") || strings.EqualFold(v, "true"))
+	case float64:
+		*g = v != 0
+	default:
+		*g = false
+	}
+
+	return nil
+}
+
+// Table is the permission of a role on one table.
+type Table struct {
+	Guid      string  `json:"guid"`
+	RoleId    string  `json:"role_id"`
+	TableSlug string  `json:"table_slug"`
+	Read      Grant   `json:"read"`
+	Write     Grant   `json:"write"`
+	Update    Grant   `json:"update"`
+	Delete    Grant   `json:"delete"`
+	IsPublic  Grant   `json:"is_public"`
+	Fields    []Field `json:"field_permissions"`
+}
+
+// Field restricts one field of a table. Fields without an entry follow the
+// permission of their table.
+type Field struct {
+	FieldId        string `json:"field_id"`
+	FieldSlug      string `json:"field_slug"`
+	Label          string `json:"label"`
+	TableSlug      string `json:"table_slug"`
+	ViewPermission Grant  `json:"view_permission"`
+	EditPermission Grant  `json:"edit_permission"`
+}
+
+// App is the permission of a role on a menu or app of the project.
+type App struct {
+	AppId  string `json:"app_id"`
+	MenuId string `json:"menu_id"`
+	Read   Grant  `json:"read"`
+	Write  Grant  `json:"write"`
+	Update Grant  `json:"update"`
+	Delete Grant  `json:"delete"`
+}
+
+// Permissions are the permissions of one role.
+type Permissions struct {
+	RoleId string
+	Tables map[string]Table
+	Apps   []App
+	// Global holds the project-wide switches of GlobalPermission, e.g.
+	// "settings_button" or "chat".
+	Global map[string]Grant
+}
+
+// FromLogin reads the permissions of a LoginResponse.
+func FromLogin(login ucodesdk.LoginResponse) (*Permissions, error) {
+	perms, err := New(login.Data.Permissions, login.Data.AppPermissions, login.Data.GlobalPermission)
+	if err != nil {
+		return nil, err
+	}
+
+	if id, ok := login.Data.Role["id"].(string); ok {
+		perms.RoleId = id
+	}
+
+	return perms, nil
+}
+
+// New builds permissions from their untyped form, as found in
+// LoginResponse.Data or auth.User.
+func New(tables, apps []map[string]any, global map[string]any) (*Permissions, error) {
+	perms := &Permissions{Tables: map[string]Table{}, Global: map[string]Grant{}}
+
+	var typedTables []Table
+	if err := convert(tables, &typedTables); err != nil {
+		return nil, fmt.Errorf("permission: decoding table permissions: %w", err)
+	}
+	for _, table := range typedTables {
+		perms.Tables[table.TableSlug] = table
+	}
+
+	if err := convert(apps, &perms.Apps); err != nil {
+		return nil, fmt.Errorf("permission: decoding app permissions: %w", err)
+	}
+
+	for key, value := range global {
+		var grant Grant
+		if err := convert(value, &grant); err == nil {
+			perms.Global[key] = grant
+		}
+	}
+
+	return perms, nil
+}
+
+func convert(from, to any) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, to)
+}
+
+// Can reports whether action is allowed on table. Tables without a
+// permission are denied.
+func (p *Permissions) Can(table string, action Action) bool {
+	t, ok := p.Tables[table]
+	if !ok {
+		return false
+	}
+
+	switch action {
+	case Read:
+		return bool(t.Read)
+	case Write:
+		return bool(t.Write)
+	case Update:
+		return bool(t.Update)
+	case Delete:
+		return bool(t.Delete)
+	default:
+		return false
+	}
+}
+
+// Check is like Can but returns an error matching ucodesdk.ErrForbidden.
+func (p *Permissions) Check(table string, action Action) error {
+	if !p.Can(table, action) {
+		return fmt.Errorf("%w: %s on %q is not allowed", ucodesdk.ErrForbidden, action, table)
+	}
+	return nil
+}
+
+// CanReadField reports whether field of table is visible.
+func (p *Permissions) CanReadField(table, field string) bool {
+	if !p.Can(table, Read) {
+		return false
+	}
+
+	if f, ok := p.field(table, field); ok {
+		return bool(f.ViewPermission)
+	}
+	return true
+}
+
+// CanWriteField reports whether field of table may be set by action, which
+// is Write or Update.
+func (p *Permissions) CanWriteField(table, field string, action Action) bool {
+	if !p.Can(table, action) {
+		return false
+	}
+
+	if f, ok := p.field(table, field); ok {
+		return bool(f.EditPermission)
+	}
+	return true
+}
+
+// CheckWrite returns an error matching ucodesdk.ErrForbidden when action is
+// not allowed on table or object sets a field the role cannot edit.
+func (p *Permissions) CheckWrite(table string, action Action, object map[string]any) error {
+	if err := p.Check(table, action); err != nil {
+		return err
+	}
+
+	for field := range object {
+		if field == "guid" {
+			continue
+		}
+		if !p.CanWriteField(table, field, action) {
+			return fmt.Errorf("%w: field %q of %q is read-only", ucodesdk.ErrForbidden, field, table)
+		}
+	}
+
+	return nil
+}
+
+// Readable returns a copy of object without the fields of table the role
+// cannot see, or nil when the table cannot be read at all.
+func (p *Permissions) Readable(table string, object map[string]any) map[string]any {
+	if !p.Can(table, Read) {
+		return nil
+	}
+
+	readable := make(map[string]any, len(object))
+	for field, value := range object {
+		if p.CanReadField(table, field) {
+			readable[field] = value
+		}
+	}
+
+	return readable
+}
+
+// Allows reports whether the global switch name is on.
+func (p *Permissions) Allows(name string) bool {
+	return bool(p.Global[name])
+}
+
+func (p *Permissions) field(table, field string) (Field, bool) {
+	for _, f := range p.Tables[table].Fields {
+		if f.FieldSlug == field || f.FieldId == field || (f.FieldSlug == "" && f.Label == field) {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
@@ -0,0 +1,78 @@
+package permission
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ucodesdk "github.com/ucode-io/ucode_sdk"
+)
+
+const loginJSON = `{
+	"data": {
+		"role": {"id": "role-1"},
+		"permissions": [
+			{
+				"table_slug": "orders", "read": "Yes", "write": "Yes", "update": "Yes", "delete": "No",
+				"field_permissions": [
+					{"field_slug": "price", "view_permission": true, "edit_permission": false},
+					{"field_slug": "margin", "view_permission": false, "edit_permission": false}
+				]
+			},
+			{"table_slug": "clients", "read": true, "write": false, "update": false, "delete": false}
+		],
+		"app_permissions": [{"menu_id": "menu-1", "read": "Yes"}],
+		"global_permission": {"chat": true, "settings_button": "No"}
+	}
+}`
+
+func testPermissions(t *testing.T) *Permissions {
+	t.Helper()
+
+	var login ucodesdk.LoginResponse
+	require.NoError(t, json.Unmarshal([]byte(loginJSON), &login))
+
+	perms, err := FromLogin(login)
+	require.NoError(t, err)
+
+	return perms
+}
+
+func TestCan(t *testing.T) {
+	perms := testPermissions(t)
+
+	assert.Equal(t, "role-1", perms.RoleId)
+	assert.True(t, perms.Can("orders", Read))
+	assert.True(t, perms.Can("orders", Write))
+	assert.False(t, perms.Can("orders", Delete))
+	assert.True(t, perms.Can("clients", Read))
+	assert.False(t, perms.Can("clients", Update))
+	assert.False(t, perms.Can("unknown", Read))
+
+	assert.NoError(t, perms.Check("orders", Update))
+	assert.ErrorIs(t, perms.Check("orders", Delete), ucodesdk.ErrForbidden)
+
+	assert.True(t, perms.Allows("chat"))
+	assert.False(t, perms.Allows("settings_button"))
+	assert.True(t, bool(perms.Apps[0].Read))
+}
+
+func TestFields(t *testing.T) {
+	perms := testPermissions(t)
+
+	assert.True(t, perms.CanReadField("orders", "price"))
+	assert.False(t, perms.CanReadField("orders", "margin"))
+	assert.True(t, perms.CanReadField("orders", "status"))
+	assert.False(t, perms.CanWriteField("orders", "price", Update))
+	assert.True(t, perms.CanWriteField("orders", "status", Update))
+	assert.False(t, perms.CanWriteField("clients", "name", Update))
+
+	assert.NoError(t, perms.CheckWrite("orders", Update, map[string]any{"guid": "1", "status": "paid"}))
+	assert.ErrorIs(t, perms.CheckWrite("orders", Update, map[string]any{"price": 10}), ucodesdk.ErrForbidden)
+	assert.ErrorIs(t, perms.CheckWrite("clients", Write, map[string]any{"name": "John"}), ucodesdk.ErrForbidden)
+
+	assert.Equal(t, map[string]any{"price": 10, "status": "paid"},
+		perms.Readable("orders", map[string]any{"price": 10, "margin": 2, "status": "paid"}))
+	assert.Nil(t, perms.Readable("unknown", map[string]any{"name": "x"}))
+}
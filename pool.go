@@ -0,0 +1,371 @@
+package ucodesdk
+
+import (
+	"cmp"
+	"context"
+	"net/http"
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultResolveTimeout bounds the calls to PoolConfig.Resolve when
+// PoolConfig.ResolveTimeout is zero.
+const DefaultResolveTimeout = 30 * time.Second
+
+// PoolConfig configures a Pool.
+type PoolConfig struct {
+	// Base holds the settings shared by every client of the pool: base URLs,
+	// timeouts, retries and the transport settings of the shared transport.
+	Base Config
+	// Resolve returns the configuration of the project identified by key,
+	// e.g. from a secret store. Its empty base URLs, RequestTimeout, Retry,
+	// Headers, middleware, Logger and Limiter are taken from Base; the
+	// project fields and TokenSource are not, and its transport settings are
+	// ignored. When nil, key is used as the AppId of Base.
+	Resolve func(ctx context.Context, key string) (*Config, error)
+	// ResolveTimeout bounds each call to Resolve. Since the client is shared
+	// by every concurrent Get of its key, Resolve is not canceled with the
+	// context of the Get that started it. Zero means DefaultResolveTimeout.
+	ResolveTimeout time.Duration
+	// MaxSize evicts the least recently used clients beyond it. Zero means
+	// no limit.
+	MaxSize int
+	// IdleTimeout evicts clients that were not used for longer. Zero means
+	// clients are kept until evicted explicitly.
+	IdleTimeout time.Duration
+}
+
+// PoolStats describes the client of one project of a Pool.
+type PoolStats struct {
+	Key       string
+	CreatedAt time.Time
+	LastUsed  time.Time
+	// Gets is the number of times the client was returned by Get.
+	Gets int64
+	// Requests and Failures count the HTTP requests sent by the client;
+	// failures are transport errors and responses with status >= 400.
+	Requests  int64
+	Failures  int64
+	InFlight  int64
+	Rotations int64
+}
+
+// Pool caches one client per project for services talking to many u-code
+// projects. Clients are created lazily on first use and share a single
+// transport, so connections are reused across projects. A Pool is safe for
+// concurrent use.
+//
+//	pool := ucodesdk.NewPool(ucodesdk.PoolConfig{
+//		Base:    ucodesdk.Config{BaseURL: ucodesdk.DefaultBaseURL},
+//		Resolve: loadProjectConfig,
+//		MaxSize: 100,
+//	})
+//	sdk, err := pool.Get(ctx, projectId)
+type Pool struct {
+	config    PoolConfig
+	transport http.RoundTripper
+	now       func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*poolEntry
+}
+
+type poolEntry struct {
+	ready chan struct{}
+	sdk   UcodeApis
+	err   error
+
+	createdAt time.Time
+	lastUsed  atomic.Int64
+	gets      atomic.Int64
+	rotations atomic.Int64
+	stats     *transportStats
+}
+
+// NewPool returns an empty pool.
+func NewPool(cfg PoolConfig) *Pool {
+	transport := cfg.Base.Transport
+	if cfg.Base.HTTPClient != nil {
+		transport = cfg.Base.HTTPClient.Transport
+	}
+	if transport == nil {
+		transport = newHTTPClient(&Config{
+			TLSConfig:           cfg.Base.TLSConfig,
+			Proxy:               cfg.Base.Proxy,
+			MaxIdleConnsPerHost: cfg.Base.MaxIdleConnsPerHost,
+		}).Transport
+	}
+
+	return &Pool{
+		config:    cfg,
+		transport: transport,
+		now:       time.Now,
+		entries:   map[string]*poolEntry{},
+	}
+}
+
+// Get returns the client of the project identified by key, creating it on
+// first use.
+func (p *Pool) Get(ctx context.Context, key string) (UcodeApis, error) {
+	p.mu.Lock()
+
+	entry, ok := p.entries[key]
+	if !ok {
+		p.evictLocked()
+		entry = &poolEntry{ready: make(chan struct{}), createdAt: p.now(), stats: &transportStats{}}
+		p.entries[key] = entry
+		go p.create(context.WithoutCancel(ctx), key, entry)
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-entry.ready:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if entry.err != nil {
+		return nil, entry.err
+	}
+
+	entry.gets.Add(1)
+	entry.lastUsed.Store(p.now().UnixNano())
+
+	return entry.sdk, nil
+}
+
+// Rotate replaces the credentials of the project identified by key with
+// cfg, e.g. after its API key was rotated. Clients returned by earlier calls
+// to Get keep using the old credentials; the statistics of the project are
+// kept.
+func (p *Pool) Rotate(key string, cfg *Config) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, ok := p.entries[key]
+	if !ok {
+		p.evictLocked()
+		entry = &poolEntry{ready: make(chan struct{}), createdAt: p.now(), stats: &transportStats{}}
+		p.entries[key] = entry
+	} else {
+		rotated := &poolEntry{ready: make(chan struct{}), createdAt: entry.createdAt, stats: entry.stats}
+		rotated.gets.Store(entry.gets.Load())
+		rotated.lastUsed.Store(entry.lastUsed.Load())
+		rotated.rotations.Store(entry.rotations.Load() + 1)
+		entry = rotated
+		p.entries[key] = entry
+	}
+
+	entry.sdk = p.client(p.merge(cfg), entry.stats)
+	entry.lastUsed.Store(p.now().UnixNano())
+	close(entry.ready)
+}
+
+// Evict removes the client of key, so the next Get resolves its
+// configuration again.
+func (p *Pool) Evict(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.entries, key)
+}
+
+// Len returns the number of clients in the pool.
+func (p *Pool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.entries)
+}
+
+// Stats returns the statistics of every client in the pool, sorted by key.
+func (p *Pool) Stats() []PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := make([]PoolStats, 0, len(p.entries))
+	for key, entry := range p.entries {
+		stats = append(stats, entry.snapshot(key))
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Key < stats[j].Key })
+
+	return stats
+}
+
+// Close evicts every client and closes the idle connections of the shared
+// transport.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	p.entries = map[string]*poolEntry{}
+	p.mu.Unlock()
+
+	if closer, ok := p.transport.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+// evictLocked removes idle clients and the least recently used ones beyond
+// MaxSize to make room for a new client. Clients still being created are
+// kept. It must be called with p.mu held.
+func (p *Pool) evictLocked() {
+	var built []string
+	for key, entry := range p.entries {
+		select {
+		case <-entry.ready:
+			built = append(built, key)
+		default:
+		}
+	}
+
+	if p.config.IdleTimeout > 0 {
+		deadline := p.now().Add(-p.config.IdleTimeout).UnixNano()
+		for _, key := range built {
+			if last := p.entries[key].lastUsed.Load(); last != 0 && last < deadline {
+				delete(p.entries, key)
+			}
+		}
+	}
+
+	if p.config.MaxSize <= 0 || len(p.entries) < p.config.MaxSize {
+		return
+	}
+
+	built = slices.DeleteFunc(built, func(key string) bool { return p.entries[key] == nil })
+	sort.Slice(built, func(i, j int) bool {
+		return p.entries[built[i]].lastUsed.Load() < p.entries[built[j]].lastUsed.Load()
+	})
+
+	for _, key := range built {
+		if len(p.entries) < p.config.MaxSize {
+			break
+		}
+		delete(p.entries, key)
+	}
+}
+
+// create builds the client of entry, removing entry from the pool when that
+// fails so that the next Get tries again.
+func (p *Pool) create(ctx context.Context, key string, entry *poolEntry) {
+	ctx, cancel := context.WithTimeout(ctx, cmp.Or(p.config.ResolveTimeout, DefaultResolveTimeout))
+	defer cancel()
+
+	entry.sdk, entry.err = p.newClient(ctx, key, entry.stats)
+
+	p.mu.Lock()
+	if entry.err != nil && p.entries[key] == entry {
+		delete(p.entries, key)
+	}
+	p.mu.Unlock()
+
+	close(entry.ready)
+}
+
+func (p *Pool) newClient(ctx context.Context, key string, stats *transportStats) (UcodeApis, error) {
+	if p.config.Resolve == nil {
+		cfg := p.config.Base
+		cfg.AppId = key
+		return p.client(&cfg, stats), nil
+	}
+
+	cfg, err := p.config.Resolve(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.client(p.merge(cfg), stats), nil
+}
+
+// merge fills the empty fields of cfg from the base configuration.
+func (p *Pool) merge(cfg *Config) *Config {
+	merged := *cfg
+	base := p.config.Base
+
+	if merged.BaseURL == "" {
+		merged.BaseURL = base.BaseURL
+	}
+	if merged.BaseAuthUrl == "" {
+		merged.BaseAuthUrl = base.BaseAuthUrl
+	}
+	if merged.RequestTimeout == 0 {
+		merged.RequestTimeout = base.RequestTimeout
+	}
+	if merged.Retry == nil {
+		merged.Retry = base.Retry
+	}
+	if merged.Headers == nil {
+		merged.Headers = base.Headers
+	}
+	if merged.Middleware == nil {
+		merged.Middleware = base.Middleware
+	}
+	if merged.ExecMiddleware == nil {
+		merged.ExecMiddleware = base.ExecMiddleware
+	}
+	if merged.Logger == nil {
+		merged.Logger = base.Logger
+	}
+	if merged.Limiter == nil {
+		merged.Limiter = base.Limiter
+	}
+
+	return &merged
+}
+
+// client builds a client of cfg on the shared transport.
+func (p *Pool) client(cfg *Config, stats *transportStats) UcodeApis {
+	timeout := cfg.RequestTimeout
+	if timeout == 0 && p.config.Base.HTTPClient != nil {
+		timeout = p.config.Base.HTTPClient.Timeout
+	}
+
+	cfg.HTTPClient = &http.Client{
+		Transport: &statsTransport{base: p.transport, stats: stats},
+		Timeout:   timeout,
+	}
+
+	return New(cfg)
+}
+
+func (e *poolEntry) snapshot(key string) PoolStats {
+	stats := PoolStats{
+		Key:       key,
+		CreatedAt: e.createdAt,
+		Gets:      e.gets.Load(),
+		Requests:  e.stats.requests.Load(),
+		Failures:  e.stats.failures.Load(),
+		InFlight:  e.stats.inFlight.Load(),
+		Rotations: e.rotations.Load(),
+	}
+	if last := e.lastUsed.Load(); last != 0 {
+		stats.LastUsed = time.Unix(0, last)
+	}
+
+	return stats
+}
+
+type transportStats struct {
+	requests, failures, inFlight atomic.Int64
+}
+
+// statsTransport counts the requests of one project on the shared
+// transport.
+type statsTransport struct {
+	base  http.RoundTripper
+	stats *transportStats
+}
+
+func (t *statsTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	t.stats.requests.Add(1)
+	t.stats.inFlight.Add(1)
+	defer t.stats.inFlight.Add(-1)
+
+	resp, err := t.base.RoundTrip(r)
+	if err != nil || resp.StatusCode >= http.StatusBadRequest {
+		t.stats.failures.Add(1)
+	}
+
+	return resp, err
+}
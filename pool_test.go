@@ -0,0 +1,181 @@
+package ucodesdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPool(t *testing.T) {
+	var mu sync.Mutex
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys = append(keys, r.Header.Get("X-API-KEY"))
+		mu.Unlock()
+
+		if r.Header.Get("X-API-KEY") == "broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var resolved atomic.Int32
+	pool := NewPool(PoolConfig{
+		Base: Config{BaseURL: server.URL, BaseAuthUrl: server.URL},
+		Resolve: func(ctx context.Context, key string) (*Config, error) {
+			resolved.Add(1)
+			if key == "missing" {
+				return nil, errors.New("unknown project")
+			}
+			return &Config{AppId: "key-" + key, ProjectId: key}, nil
+		},
+	})
+	defer pool.Close()
+
+	t.Run("creates clients once", func(t *testing.T) {
+		var wg sync.WaitGroup
+		clients := make([]UcodeApis, 20)
+		for i := range clients {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				sdk, err := pool.Get(context.Background(), "p1")
+				assert.NoError(t, err)
+				clients[i] = sdk
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), resolved.Load())
+		for _, sdk := range clients {
+			assert.Same(t, clients[0], sdk)
+		}
+
+		_, _, err := clients[0].Items("houses").GetList().Exec()
+		require.NoError(t, err)
+		assert.Equal(t, "key-p1", keys[len(keys)-1])
+	})
+
+	t.Run("resolve errors are not cached", func(t *testing.T) {
+		_, err := pool.Get(context.Background(), "missing")
+		assert.EqualError(t, err, "unknown project")
+		assert.Equal(t, 1, pool.Len())
+	})
+
+	t.Run("rotate", func(t *testing.T) {
+		pool.Rotate("p1", &Config{AppId: "broken", ProjectId: "p1"})
+
+		sdk, err := pool.Get(context.Background(), "p1")
+		require.NoError(t, err)
+		_, _, err = sdk.Items("houses").GetList().Exec()
+		require.Error(t, err)
+		assert.Equal(t, "broken", keys[len(keys)-1])
+
+		stats := pool.Stats()
+		require.Len(t, stats, 1)
+		assert.Equal(t, "p1", stats[0].Key)
+		assert.Equal(t, int64(2), stats[0].Requests)
+		assert.Equal(t, int64(1), stats[0].Failures)
+		assert.Equal(t, int64(21), stats[0].Gets)
+		assert.Equal(t, int64(1), stats[0].Rotations)
+		assert.Zero(t, stats[0].InFlight)
+	})
+
+	t.Run("evict", func(t *testing.T) {
+		pool.Evict("p1")
+		assert.Zero(t, pool.Len())
+
+		_, err := pool.Get(context.Background(), "p1")
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), resolved.Load())
+	})
+}
+
+func TestPoolCanceledGet(t *testing.T) {
+	resolving := make(chan struct{})
+	release := make(chan struct{})
+	pool := NewPool(PoolConfig{
+		Base: Config{BaseURL: "http://localhost"},
+		Resolve: func(ctx context.Context, key string) (*Config, error) {
+			close(resolving)
+			<-release
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			return &Config{AppId: key}, nil
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+	go func() {
+		_, err := pool.Get(ctx, "p1")
+		first <- err
+	}()
+	<-resolving
+
+	second := make(chan error)
+	go func() {
+		_, err := pool.Get(context.Background(), "p1")
+		second <- err
+	}()
+
+	cancel()
+	assert.ErrorIs(t, <-first, context.Canceled)
+
+	close(release)
+	require.NoError(t, <-second)
+	assert.Equal(t, 1, pool.Len())
+}
+
+func TestPoolEviction(t *testing.T) {
+	now := time.Unix(0, 0)
+	pool := NewPool(PoolConfig{
+		Base:        Config{BaseURL: "http://localhost"},
+		MaxSize:     2,
+		IdleTimeout: time.Minute,
+	})
+	pool.now = func() time.Time { return now }
+
+	get := func(key string) {
+		t.Helper()
+		now = now.Add(time.Second)
+		_, err := pool.Get(context.Background(), key)
+		require.NoError(t, err)
+	}
+
+	get("a")
+	get("b")
+	get("a")
+	get("c")
+
+	var keys []string
+	for _, stats := range pool.Stats() {
+		keys = append(keys, stats.Key)
+	}
+	assert.Equal(t, []string{"a", "c"}, keys)
+
+	// Getting a cached client of a full pool evicts nothing.
+	get("a")
+	get("c")
+	assert.Equal(t, 2, pool.Len())
+	gets := map[string]int64{}
+	for _, stats := range pool.Stats() {
+		gets[stats.Key] = stats.Gets
+	}
+	assert.Equal(t, map[string]int64{"a": 3, "c": 2}, gets)
+
+	now = now.Add(2 * time.Minute)
+	get("d")
+	assert.Equal(t, 1, pool.Len())
+}
@@ -0,0 +1,203 @@
+/*
+Package query builds type-safe filters for GetList.
+
+Conditions compile to the map the /v2/items/{collection} endpoint expects in
+its data query parameter:
+
+	sdk.Items("orders").
+		GetList().
+		Where(
+			query.Eq("status", "paid"),
+			query.Between("created_at", from, to),
+			query.Or(query.Gt("amount", 100), query.In("client_id", vip...)),
+		).
+		Exec()
+*/
+package query
+
+import (
+	"maps"
+	"regexp"
+	"strings"
+)
+
+// Condition is a single filter or a combination of filters.
+type Condition interface {
+	// Build returns the filter as it is sent to u-code.
+	Build() map[string]any
+}
+
+// Filter is a Condition given as a raw u-code filter map. It is the escape
+// hatch for operators the package does not cover.
+type Filter map[string]any
+
+func (f Filter) Build() map[string]any {
+	return maps.Clone(map[string]any(f))
+}
+
+// Path joins the parts of a field path with dots, e.g. a field of a related
+// table: Path("client_id_data", "name").
+func Path(parts ...string) string {
+	return strings.Join(parts, ".")
+}
+
+// Eq matches items whose field equals value.
+func Eq(field string, value any) Condition {
+	return Filter{field: value}
+}
+
+// Ne matches items whose field differs from value.
+func Ne(field string, value any) Condition {
+	return operator(field, "$ne", value)
+}
+
+// In matches items whose field equals one of values.
+func In[V any](field string, values ...V) Condition {
+	return operator(field, "$in", nonNil(values))
+}
+
+// NotIn matches items whose field equals none of values.
+func NotIn[V any](field string, values ...V) Condition {
+	return operator(field, "$nin", nonNil(values))
+}
+
+// Gt matches items whose field is greater than value.
+func Gt(field string, value any) Condition {
+	return operator(field, "$gt", value)
+}
+
+// Gte matches items whose field is greater than or equal to value.
+func Gte(field string, value any) Condition {
+	return operator(field, "$gte", value)
+}
+
+// Lt matches items whose field is less than value.
+func Lt(field string, value any) Condition {
+	return operator(field, "$lt", value)
+}
+
+// Lte matches items whose field is less than or equal to value.
+func Lte(field string, value any) Condition {
+	return operator(field, "$lte", value)
+}
+
+// Between matches items whose field lies in [from, to]. It works for
+// numbers as well as dates given as time.Time or RFC 3339 strings.
+func Between(field string, from, to any) Condition {
+	return Filter{field: map[string]any{"$gte": from, "$lte": to}}
+}
+
+// Contains matches items whose text field contains substr. Regular
+// expression metacharacters in substr are matched literally.
+func Contains(field, substr string) Condition {
+	return operator(field, "$regex", regexp.QuoteMeta(substr))
+}
+
+// Exists matches items that have (or lack) a value for field.
+func Exists(field string, exists bool) Condition {
+	return operator(field, "$exists", exists)
+}
+
+// And matches items satisfying every condition. Conditions on distinct
+// fields, or distinct operators of one field, are merged into a single
+// filter; anything else is combined with $and.
+func And(conditions ...Condition) Condition {
+	return and(conditions)
+}
+
+// Or matches items satisfying at least one condition.
+func Or(conditions ...Condition) Condition {
+	return or(conditions)
+}
+
+func operator(field, op string, value any) Condition {
+	return Filter{field: map[string]any{op: value}}
+}
+
+func nonNil[V any](values []V) []V {
+	if values == nil {
+		return []V{}
+	}
+	return values
+}
+
+type and []Condition
+
+func (a and) Build() map[string]any {
+	merged := map[string]any{}
+	var clauses []any
+
+	for _, condition := range a {
+		built := condition.Build()
+		if !merge(merged, built) {
+			clauses = append(clauses, built)
+		}
+	}
+
+	if len(clauses) > 0 {
+		merged["$and"] = append(toSlice(merged["$and"]), clauses...)
+	}
+
+	return merged
+}
+
+type or []Condition
+
+func (o or) Build() map[string]any {
+	clauses := make([]any, 0, len(o))
+	for _, condition := range o {
+		clauses = append(clauses, condition.Build())
+	}
+
+	return map[string]any{"$or": clauses}
+}
+
+// merge adds src to dst when none of its keys conflict, and reports whether
+// it did. Operator maps of the same field are merged key by key.
+func merge(dst, src map[string]any) bool {
+	for key, value := range src {
+		existing, ok := dst[key]
+		if !ok {
+			continue
+		}
+
+		existingOps, ok1 := existing.(map[string]any)
+		valueOps, ok2 := value.(map[string]any)
+		if !ok1 || !ok2 || !isOperatorMap(existingOps) || !isOperatorMap(valueOps) {
+			return false
+		}
+		for op := range valueOps {
+			if _, ok := existingOps[op]; ok {
+				return false
+			}
+		}
+	}
+
+	for key, value := range src {
+		if valueOps, ok := value.(map[string]any); ok {
+			if existingOps, ok := dst[key].(map[string]any); ok {
+				combined := maps.Clone(existingOps)
+				maps.Copy(combined, valueOps)
+				dst[key] = combined
+				continue
+			}
+		}
+		dst[key] = value
+	}
+
+	return true
+}
+
+func isOperatorMap(m map[string]any) bool {
+	for key := range m {
+		if !strings.HasPrefix(key, "$") {
+			return false
+		}
+	}
+	return len(m) > 0
+}
+
+func toSlice(value any) []any {
+	slice, _ := value.([]any)
+	return slice
+}
@@ -0,0 +1,68 @@
+package query
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func assertJSON(t *testing.T, expected string, condition Condition) {
+	t.Helper()
+
+	data, err := json.Marshal(condition.Build())
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, expected, string(data))
+	}
+}
+
+func TestConditions(t *testing.T) {
+	assertJSON(t, `{"status":"paid"}`, Eq("status", "paid"))
+	assertJSON(t, `{"status":{"$ne":"draft"}}`, Ne("status", "draft"))
+	assertJSON(t, `{"status":{"$in":["paid","shipped"]}}`, In("status", "paid", "shipped"))
+	assertJSON(t, `{"status":{"$in":[]}}`, In[string]("status"))
+	assertJSON(t, `{"room_count":{"$nin":[1,2]}}`, NotIn("room_count", 1, 2))
+	assertJSON(t, `{"price":{"$gt":100}}`, Gt("price", 100))
+	assertJSON(t, `{"price":{"$gte":100}}`, Gte("price", 100))
+	assertJSON(t, `{"price":{"$lt":100}}`, Lt("price", 100))
+	assertJSON(t, `{"price":{"$lte":100}}`, Lte("price", 100))
+	assertJSON(t, `{"name":{"$regex":"house"}}`, Contains("name", "house"))
+	assertJSON(t, `{"name":{"$regex":"a\\+b \\(c\\)"}}`, Contains("name", "a+b (c)"))
+	assertJSON(t, `{"photo":{"$exists":false}}`, Exists("photo", false))
+	assertJSON(t, `{"client_id_data.name":"John"}`, Eq(Path("client_id_data", "name"), "John"))
+	assertJSON(t, `{"price":{"$size":2}}`, Filter{"price": map[string]any{"$size": 2}})
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	assertJSON(t, `{"created_at":{"$gte":"2024-01-01T00:00:00Z","$lte":"2024-02-01T00:00:00Z"}}`, Between("created_at", from, to))
+}
+
+func TestAnd(t *testing.T) {
+	assertJSON(t, `{"status":"paid","price":{"$gte":100,"$lt":500}}`,
+		And(Eq("status", "paid"), Gte("price", 100), Lt("price", 500)))
+
+	assertJSON(t, `{"status":"paid","$and":[{"status":"shipped"}]}`,
+		And(Eq("status", "paid"), Eq("status", "shipped")))
+
+	assertJSON(t, `{"price":{"$gte":100,"$lte":200},"$and":[{"price":{"$gte":150}}]}`,
+		And(Between("price", 100, 200), Gte("price", 150)))
+
+	assertJSON(t, `{"$or":[{"a":1},{"b":2}],"$and":[{"$or":[{"c":3},{"d":4}]}]}`,
+		And(Or(Eq("a", 1), Eq("b", 2)), Or(Eq("c", 3), Eq("d", 4))))
+
+	assertJSON(t, `{}`, And())
+}
+
+func TestOr(t *testing.T) {
+	assertJSON(t, `{"$or":[{"status":"paid"},{"price":{"$gt":100},"name":"house"}]}`,
+		Or(Eq("status", "paid"), And(Gt("price", 100), Eq("name", "house"))))
+}
+
+func TestFilterIsNotShared(t *testing.T) {
+	filter := Filter{"status": "paid"}
+	built := filter.Build()
+	built["status"] = "draft"
+
+	assert.Equal(t, "paid", filter["status"])
+}
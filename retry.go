@@ -0,0 +1,135 @@
+package ucodesdk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how failed calls are retried. It is applied to
+// idempotent operations (GetList, GetSingle, aggregation and Delete) and to
+// Create/Update calls that carry an idempotency key.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, the first one included.
+	// Values below 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the wait before the second attempt. Every following
+	// wait is multiplied by Multiplier and capped by MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	// Jitter is the fraction (0..1) of each wait that is randomized so that
+	// concurrent clients do not retry in lockstep.
+	Jitter float64
+	// RetryableStatuses lists the HTTP status codes worth another attempt.
+	RetryableStatuses []int
+}
+
+// DefaultRetryPolicy returns a policy with three attempts, exponential
+// backoff starting at 100ms and retries on 429, 502, 503 and 504.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:       3,
+		InitialBackoff:    100 * time.Millisecond,
+		MaxBackoff:        5 * time.Second,
+		Multiplier:        2,
+		Jitter:            0.2,
+		RetryableStatuses: []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+	}
+}
+
+// retryable reports whether err is a transient failure: one of the
+// configured status codes or a dropped connection.
+func (p *RetryPolicy) retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return slices.Contains(p.RetryableStatuses, apiErr.StatusCode)
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// backoff returns the wait before attempt+1. A Retry-After header sent with
+// the failed response takes precedence when it asks for a longer wait.
+func (p *RetryPolicy) backoff(attempt int, err error) time.Duration {
+	wait := p.InitialBackoff
+	for i := 1; i < attempt; i++ {
+		wait = time.Duration(float64(wait) * max(p.Multiplier, 1))
+		if p.MaxBackoff > 0 && wait >= p.MaxBackoff {
+			wait = p.MaxBackoff
+			break
+		}
+	}
+
+	if p.Jitter > 0 && wait > 0 {
+		wait -= time.Duration(rand.Float64() * min(p.Jitter, 1) * float64(wait))
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if retryAfter := parseRetryAfter(apiErr.Header.Get("Retry-After")); retryAfter > wait {
+			wait = retryAfter
+		}
+	}
+
+	return wait
+}
+
+// parseRetryAfter reads a Retry-After header given either in seconds or as
+// an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0)
+	}
+
+	return 0
+}
+
+// withRetry runs attempt until it succeeds, fails permanently or the policy
+// gives up. Non idempotent calls are attempted exactly once.
+func (a *object) withRetry(ctx context.Context, idempotent bool, attempt func(n int) ([]byte, error)) ([]byte, error) {
+	policy := a.config.Retry
+	if policy == nil || !idempotent {
+		return attempt(1)
+	}
+
+	for n := 1; ; n++ {
+		respByte, err := attempt(n)
+		if err == nil || n >= policy.MaxAttempts || !policy.retryable(err) {
+			return respByte, err
+		}
+
+		timer := time.NewTimer(policy.backoff(n, err))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return respByte, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package ucodesdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newFlakyServer answers the first failures requests with status and every
+// following one with 200.
+func newFlakyServer(t *testing.T, failures int32, status int, header http.Header) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			for key, values := range header {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(`{"status":"OK"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &calls
+}
+
+func testRetryPolicy() *RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxBackoff = 5 * time.Millisecond
+	return policy
+}
+
+func TestRetry(t *testing.T) {
+	t.Run("idempotent calls are retried", func(t *testing.T) {
+		server, calls := newFlakyServer(t, 2, http.StatusServiceUnavailable, nil)
+		ucodeApi := New(&Config{BaseURL: server.URL, Retry: testRetryPolicy()})
+
+		_, _, err := ucodeApi.Items("houses").GetList().Exec()
+		assert.NoError(t, err)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("gives up after MaxAttempts", func(t *testing.T) {
+		server, calls := newFlakyServer(t, 10, http.StatusBadGateway, nil)
+		ucodeApi := New(&Config{BaseURL: server.URL, Retry: testRetryPolicy()})
+
+		_, err := ucodeApi.Items("houses").Delete().Single("1").Exec()
+		assert.ErrorIs(t, err, ErrServer)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("non retryable status", func(t *testing.T) {
+		server, calls := newFlakyServer(t, 10, http.StatusNotFound, nil)
+		ucodeApi := New(&Config{BaseURL: server.URL, Retry: testRetryPolicy()})
+
+		_, _, err := ucodeApi.Items("houses").GetSingle("1").Exec()
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("create is not retried without idempotency key", func(t *testing.T) {
+		server, calls := newFlakyServer(t, 1, http.StatusServiceUnavailable, nil)
+		ucodeApi := New(&Config{BaseURL: server.URL, Retry: testRetryPolicy()})
+
+		_, _, err := ucodeApi.Items("houses").Create(map[string]any{}).Exec()
+		assert.Error(t, err)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("create is retried with idempotency key", func(t *testing.T) {
+		var keys []string
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			keys = append(keys, r.Header.Get("Idempotency-Key"))
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusGatewayTimeout)
+				return
+			}
+			w.Write([]byte(`{}`))
+		}))
+		defer server.Close()
+		ucodeApi := New(&Config{BaseURL: server.URL, Retry: testRetryPolicy()})
+
+		_, _, err := ucodeApi.Items("houses").Create(map[string]any{}).IdempotencyKey("key-1").Exec()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"key-1", "key-1"}, keys)
+	})
+
+	t.Run("disabled without policy", func(t *testing.T) {
+		server, calls := newFlakyServer(t, 1, http.StatusServiceUnavailable, nil)
+		ucodeApi := New(&Config{BaseURL: server.URL})
+
+		_, _, err := ucodeApi.Items("houses").GetList().Exec()
+		assert.Error(t, err)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("context canceled during backoff", func(t *testing.T) {
+		server, calls := newFlakyServer(t, 10, http.StatusServiceUnavailable, nil)
+		policy := testRetryPolicy()
+		policy.InitialBackoff = time.Hour
+		policy.MaxBackoff = time.Hour
+		ucodeApi := New(&Config{BaseURL: server.URL, Retry: policy})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, _, err := ucodeApi.Items("houses").GetList().ExecContext(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := &RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2}
+
+	assert.Equal(t, 100*time.Millisecond, policy.backoff(1, errors.New("reset")))
+	assert.Equal(t, 200*time.Millisecond, policy.backoff(2, errors.New("reset")))
+	assert.Equal(t, 400*time.Millisecond, policy.backoff(3, errors.New("reset")))
+	assert.Equal(t, time.Second, policy.backoff(10, errors.New("reset")))
+
+	retryAfter := &APIError{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"3"}}}
+	assert.Equal(t, 3*time.Second, policy.backoff(1, retryAfter))
+
+	policy.Jitter = 0.5
+	for range 100 {
+		wait := policy.backoff(1, errors.New("reset"))
+		assert.GreaterOrEqual(t, wait, 50*time.Millisecond)
+		assert.LessOrEqual(t, wait, 100*time.Millisecond)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	assert.Equal(t, time.Duration(0), parseRetryAfter(""))
+	assert.Equal(t, 2*time.Second, parseRetryAfter("2"))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon"))
+
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	assert.InDelta(t, float64(time.Minute), float64(parseRetryAfter(date)), float64(2*time.Second))
+}
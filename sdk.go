@@ -1,9 +1,8 @@
 package ucodesdk
 
 import (
-	"bytes"
-	"encoding/json"
-	"io"
+	"context"
+	"errors"
 	"net/http"
 )
 
@@ -66,76 +65,193 @@ type UcodeApis interface {
 	Function(path string) FunctionI
 	Config() *Config
 	DoRequest(url string, method string, body any, headers map[string]string) ([]byte, error)
+	DoRequestContext(ctx context.Context, url string, method string, body any, headers map[string]string) ([]byte, error)
 }
 
 func New(cfg *Config) UcodeApis {
 	return &object{
 		config: cfg,
+		client: newHTTPClient(cfg),
 	}
 }
 
 // UcodeAPI struct implements UcodeAPIInterface
 type object struct {
 	config *Config
+	client *http.Client
 }
 
 func (u *object) Config() *Config {
 	return u.config
 }
 
+// DoRequest sends a JSON request and returns the raw response body.
 func DoRequest(url string, method string, body any, headers map[string]string) ([]byte, error) {
-	data, err := json.Marshal(&body)
-	if err != nil {
-		return nil, err
-	}
+	return DoRequestContext(context.Background(), url, method, body, headers)
+}
 
-	client := &http.Client{}
+// DoRequestContext is like DoRequest but the request is bound to ctx, so it
+// is aborted as soon as ctx is canceled or its deadline passes.
+func DoRequestContext(ctx context.Context, url string, method string, body any, headers map[string]string) ([]byte, error) {
+	return doRequest(ctx, http.DefaultClient, url, method, body, headers)
+}
 
-	request, err := http.NewRequest(method, url, bytes.NewBuffer(data))
-	if err != nil {
-		return nil, err
-	}
+func (a *object) DoRequest(url string, method string, body any, headers map[string]string) ([]byte, error) {
+	return a.DoRequestContext(context.Background(), url, method, body, headers)
+}
 
-	// Add headers from the map
-	for key, value := range headers {
-		request.Header.Add(key, value)
+func (a *object) DoRequestContext(ctx context.Context, url string, method string, body any, headers map[string]string) ([]byte, error) {
+	return doRequest(ctx, a.client, url, method, body, headers)
+}
+
+// apiCall is a single request a builder sends to the u-code API.
+type apiCall struct {
+	operation  string
+	collection string
+	url        string
+	method     string
+	body       any
+	headers    map[string]string
+	idempotent bool
+	// authorized calls carry the credentials returned by authHeaders.
+	authorized bool
+}
+
+// send issues c through the middleware and the SDK client, retrying it
+// according to Config.Retry when the call is idempotent.
+func (a *object) send(ctx context.Context, c apiCall) ([]byte, error) {
+	var exec RoundTripFunc = func(ctx context.Context, call *Call) (*Result, error) {
+		result, token, err := a.sendAttempts(ctx, c, call)
+		if token != nil && errors.Is(err, ErrUnauthorized) {
+			if ts, ok := a.config.TokenSource.(TokenInvalidator); ok {
+				// The token was rejected before it expired, try once more
+				// with a fresh one. When no fresh token can be had, the
+				// rejection is the error of the call.
+				ts.Invalidate(token)
+				if retried, token, retryErr := a.sendAttempts(ctx, c, call); token != nil {
+					result, err = retried, retryErr
+				}
+			}
+		}
+
+		return result, err
+	}
+	for i := len(a.config.ExecMiddleware) - 1; i >= 0; i-- {
+		exec = a.config.ExecMiddleware[i](exec)
 	}
 
-	resp, err := client.Do(request)
-	if err != nil {
+	result, err := exec(ctx, &Call{
+		Operation:  c.operation,
+		Collection: c.collection,
+		Method:     c.method,
+		URL:        c.url,
+		Headers:    c.headers,
+		Body:       c.body,
+	})
+	if result == nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	respByte, err := io.ReadAll(resp.Body)
+	return result.Body, err
+}
 
-	return respByte, err
+// sendAttempts issues call through the SDK client, retrying it according to
+// Config.Retry when c is idempotent. It also returns the token the last attempt
+// was authenticated with, if any.
+func (a *object) sendAttempts(ctx context.Context, c apiCall, call *Call) (*Result, *Token, error) {
+	var (
+		result *Result
+		token  *Token
+	)
+	_, err := a.withRetry(ctx, c.idempotent, func(attempt int) ([]byte, error) {
+		var (
+			headers map[string]string
+			err     error
+		)
+		headers, token, err = a.requestHeaders(ctx, c.authorized, call.Headers)
+		if err != nil {
+			result = nil
+			return nil, err
+		}
+
+		result, err = a.roundTrip(ctx, &Call{
+			Operation:  call.Operation,
+			Collection: call.Collection,
+			Method:     call.Method,
+			URL:        call.URL,
+			Headers:    headers,
+			Body:       call.Body,
+			Attempt:    attempt,
+		})
+		if result == nil {
+			return nil, err
+		}
+
+		return result.Body, err
+	})
+
+	return result, token, err
 }
 
-func (a *object) DoRequest(url string, method string, body any, headers map[string]string) ([]byte, error) {
-	data, err := json.Marshal(&body)
-	if err != nil {
-		return nil, err
+// requestHeaders merges the headers of a call, from lowest to highest
+// precedence: Config.Headers, the resource and environment ids of the
+// Config, the credentials of authorized calls and the headers of the call.
+// It also returns the token the credentials carry, if any.
+func (a *object) requestHeaders(ctx context.Context, authorized bool, headers map[string]string) (map[string]string, *Token, error) {
+	merged := map[string]string{}
+	set := func(headers map[string]string) {
+		for key, value := range headers {
+			merged[http.CanonicalHeaderKey(key)] = value
+		}
 	}
 
-	client := &http.Client{}
+	set(a.config.Headers)
+	if a.config.ResourceId != "" {
+		merged["Resource-Id"] = a.config.ResourceId
+	}
+	if a.config.EnvironmentId != "" {
+		merged["Environment-Id"] = a.config.EnvironmentId
+	}
 
-	request, err := http.NewRequest(method, url, bytes.NewBuffer(data))
-	if err != nil {
-		return nil, err
+	var token *Token
+	if authorized {
+		auth, t, err := a.authHeaders(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		set(auth)
+		token = t
 	}
 
-	// Add headers from the map
-	for key, value := range headers {
-		request.Header.Add(key, value)
+	set(headers)
+
+	return merged, token, nil
+}
+
+// authHeaders returns the headers authenticating a call: a Bearer token from
+// Config.TokenSource when one is set, along with that token, the app API key
+// otherwise.
+func (a *object) authHeaders(ctx context.Context) (map[string]string, *Token, error) {
+	if a.config.TokenSource != nil {
+		token, err := a.config.TokenSource.Token(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return map[string]string{"Authorization": "Bearer " + token.AccessToken}, token, nil
 	}
 
-	resp, err := client.Do(request)
-	if err != nil {
+	return map[string]string{
+		"authorization": "API-KEY",
+		"X-API-KEY":     a.config.AppId,
+	}, nil, nil
+}
+
+func doRequest(ctx context.Context, client *http.Client, url string, method string, body any, headers map[string]string) ([]byte, error) {
+	result, err := roundTrip(ctx, client, &Call{Method: method, URL: url, Body: body, Headers: headers, Attempt: 1})
+	if result == nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	respByte, err := io.ReadAll(resp.Body)
-	return respByte, err
+	return result.Body, err
 }
@@ -0,0 +1,313 @@
+package ucodesdk
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"sync"
+	"time"
+)
+
+// TokenSource supplies the access token of user-scoped calls. Set it on
+// Config.TokenSource, or use WithTokenSource, to send
+// "Authorization: Bearer <token>" instead of the X-API-KEY header.
+type TokenSource interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+// TokenInvalidator is implemented by token sources that can drop a token the
+// API rejected. A call failing with ErrUnauthorized invalidates its token and
+// is retried once with a fresh one.
+type TokenInvalidator interface {
+	Invalidate(token *Token)
+}
+
+// DefaultRefreshBefore is how long before its expiry a token is refreshed.
+const DefaultRefreshBefore = time.Minute
+
+// refreshRetryDelay is how long a token source waits before retrying a
+// failed refresh while its token is still valid.
+const refreshRetryDelay = 10 * time.Second
+
+// tokenFetchTimeout bounds a login or refresh, which outlives the caller
+// that started it.
+const tokenFetchTimeout = time.Minute
+
+var (
+	// ErrNoToken is returned when a login or refresh response carries no
+	// token.
+	ErrNoToken = errors.New("ucode: response has no access token")
+	// ErrTokenExpired is returned by a token source that has no credentials
+	// to log in again once its token expired and could not be refreshed.
+	ErrTokenExpired = errors.New("ucode: access token expired")
+)
+
+// LoginTokenSource logs in on first use, caches the token and refreshes it
+// with the refresh token shortly before it expires, logging in again when
+// the refresh fails. It is safe for concurrent use; concurrent callers
+// share a single login or refresh.
+type LoginTokenSource struct {
+	auth  AuthI
+	login func(ctx context.Context) (LoginResponse, error)
+
+	// RefreshBefore is how long before expiry the token is refreshed.
+	// Zero means DefaultRefreshBefore.
+	RefreshBefore time.Duration
+
+	mu    sync.Mutex
+	token *Token
+	// expiresAt is zero when the token does not tell when it expires.
+	expiresAt time.Time
+	refreshAt time.Time
+	// invalid is set once the API rejected the cached token.
+	invalid bool
+	// inflight is the login or refresh in progress, if any.
+	inflight *tokenCall
+	now      func() time.Time
+}
+
+// NewTokenSource returns a token source logging in through sdk with the
+// /v2/login body login, e.g.
+//
+//	ts := ucodesdk.NewTokenSource(sdk, map[string]any{"username": "john", "password": "secret"})
+//	user := ucodesdk.WithTokenSource(sdk, ts)
+func NewTokenSource(sdk UcodeApis, login map[string]any) *LoginTokenSource {
+	auth := sdk.Auth()
+	return &LoginTokenSource{
+		auth: auth,
+		login: func(ctx context.Context) (LoginResponse, error) {
+			resp, _, err := auth.Login(maps.Clone(login)).ExecContext(ctx)
+			return resp, err
+		},
+		now: time.Now,
+	}
+}
+
+// NewStrategyTokenSource is like NewTokenSource but logs in with strategy,
+// e.g. a PasswordLogin or GoogleLogin.
+func NewStrategyTokenSource(sdk UcodeApis, strategy LoginStrategy) *LoginTokenSource {
+	auth := sdk.Auth()
+	return &LoginTokenSource{
+		auth: auth,
+		login: func(ctx context.Context) (LoginResponse, error) {
+			resp, _, err := auth.LoginWith(strategy).ExecContext(ctx)
+			return resp, err
+		},
+		now: time.Now,
+	}
+}
+
+// RefreshingTokenSource returns a token source starting from token, e.g. the
+// token of a LoginResponse, and refreshing it before it expires. Once the
+// token expired and cannot be refreshed, Token returns ErrTokenExpired.
+func RefreshingTokenSource(sdk UcodeApis, token *Token) *LoginTokenSource {
+	s := &LoginTokenSource{auth: sdk.Auth(), now: time.Now}
+	if token != nil && token.AccessToken != "" {
+		s.set(token, s.now())
+	}
+	return s
+}
+
+// StaticTokenSource returns a token source that always returns token and
+// never refreshes it.
+func StaticTokenSource(token *Token) TokenSource {
+	return staticTokenSource{token: token}
+}
+
+type staticTokenSource struct {
+	token *Token
+}
+
+func (s staticTokenSource) Token(context.Context) (*Token, error) {
+	if s.token == nil || s.token.AccessToken == "" {
+		return nil, ErrNoToken
+	}
+	return s.token, nil
+}
+
+// WithTokenSource returns a client sharing the configuration and connections
+// of sdk whose calls are authenticated by ts.
+func WithTokenSource(sdk UcodeApis, ts TokenSource) UcodeApis {
+	cfg := *sdk.Config()
+	cfg.TokenSource = ts
+
+	if o, ok := sdk.(*object); ok {
+		return &object{config: &cfg, client: o.client}
+	}
+
+	return New(&cfg)
+}
+
+// Token returns a valid access token, logging in or refreshing as needed.
+// Concurrent callers wait for the same login or refresh, each giving up when
+// its own ctx is done.
+func (s *LoginTokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	if s.token != nil && !s.invalid && s.now().Before(s.refreshAt) {
+		token := s.token
+		s.mu.Unlock()
+		return token, nil
+	}
+
+	call := s.inflight
+	if call == nil {
+		call = &tokenCall{done: make(chan struct{})}
+		s.inflight = call
+		go s.fetch(context.WithoutCancel(ctx), call)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.token, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// tokenCall is a login or refresh shared by the callers of Token.
+type tokenCall struct {
+	done  chan struct{}
+	token *Token
+	err   error
+}
+
+// fetch runs call. It is not canceled with the caller that started it, as
+// other callers may be waiting for it, but bounded by tokenFetchTimeout.
+func (s *LoginTokenSource) fetch(ctx context.Context, call *tokenCall) {
+	ctx, cancel := context.WithTimeout(ctx, tokenFetchTimeout)
+	defer cancel()
+
+	call.token, call.err = s.fetchToken(ctx)
+
+	s.mu.Lock()
+	s.inflight = nil
+	s.mu.Unlock()
+
+	close(call.done)
+}
+
+// fetchToken refreshes the cached token or logs in again. s.mu is only held
+// while the cache is read and updated, not during the calls to the API.
+func (s *LoginTokenSource) fetchToken(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	token := s.token
+	s.mu.Unlock()
+
+	if token != nil && token.RefreshToken != "" {
+		resp, _, err := s.auth.RefreshToken(token.RefreshToken).ExecContext(ctx)
+
+		s.mu.Lock()
+		now := s.now()
+		if err == nil && resp.Data.Token != nil && resp.Data.Token.AccessToken != "" {
+			s.set(resp.Data.Token, now)
+			s.mu.Unlock()
+			return resp.Data.Token, nil
+		}
+
+		// The token is still usable, try to refresh again a bit later.
+		if s.usable(now) {
+			s.refreshAt = now.Add(refreshRetryDelay)
+			if !s.expiresAt.IsZero() && s.expiresAt.Before(s.refreshAt) {
+				s.refreshAt = s.expiresAt
+			}
+			s.mu.Unlock()
+			return token, nil
+		}
+		s.mu.Unlock()
+	}
+
+	if s.login == nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if s.token == nil {
+			return nil, ErrNoToken
+		}
+		if s.usable(s.now()) {
+			return s.token, nil
+		}
+		return nil, ErrTokenExpired
+	}
+
+	resp, err := s.login(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Data.Token == nil || resp.Data.Token.AccessToken == "" {
+		return nil, ErrNoToken
+	}
+
+	s.mu.Lock()
+	s.set(resp.Data.Token, s.now())
+	s.mu.Unlock()
+
+	return resp.Data.Token, nil
+}
+
+// Invalidate drops token when it is the cached one, so that the next Token
+// call refreshes it or logs in again.
+func (s *LoginTokenSource) Invalidate(token *Token) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != nil && token != nil && s.token.AccessToken == token.AccessToken {
+		s.invalid = true
+	}
+}
+
+// usable reports whether the cached token can still be sent: the API did not
+// reject it and it has not expired, an unknown expiry counting as valid. It
+// must be called with s.mu held.
+func (s *LoginTokenSource) usable(now time.Time) bool {
+	return !s.invalid && (s.expiresAt.IsZero() || now.Before(s.expiresAt))
+}
+
+// set caches token and schedules its refresh. It must be called with s.mu
+// held.
+func (s *LoginTokenSource) set(token *Token, now time.Time) {
+	refreshBefore := s.RefreshBefore
+	if refreshBefore <= 0 {
+		refreshBefore = DefaultRefreshBefore
+	}
+
+	s.token = token
+	s.invalid = false
+	s.expiresAt = parseTokenTime(token.ExpiresAt)
+
+	deadline := s.expiresAt
+	if token.RefreshInSeconds > 0 {
+		refreshIn := now.Add(time.Duration(token.RefreshInSeconds) * time.Second)
+		if deadline.IsZero() || refreshIn.Before(deadline) {
+			deadline = refreshIn
+		}
+	}
+
+	var refreshAt time.Time
+	if deadline.IsZero() {
+		// Nothing tells when the token expires: keep it until the API rejects
+		// it and the failed call invalidates it.
+		refreshAt = now.AddDate(100, 0, 0)
+	} else {
+		refreshAt = deadline.Add(-refreshBefore)
+		// A token living less than twice RefreshBefore is refreshed half way
+		// through its life, not on every call.
+		if half := now.Add(deadline.Sub(now) / 2); refreshAt.Before(half) {
+			refreshAt = half
+		}
+	}
+	s.refreshAt = refreshAt
+}
+
+// tokenTimeLayouts are the formats in which the auth service returns
+// Token.ExpiresAt.
+var tokenTimeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05"}
+
+func parseTokenTime(value string) time.Time {
+	for _, layout := range tokenTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
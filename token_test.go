@@ -0,0 +1,287 @@
+package ucodesdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type authCounters struct {
+	logins, refreshes, failedRefreshes atomic.Int32
+	failRefresh                        atomic.Bool
+	// revoked is an access token item lists reject with 401.
+	revoked atomic.Value
+	// refreshIn is the refresh_in_seconds of the tokens, 3600 when zero.
+	refreshIn atomic.Int32
+}
+
+// newAuthServer issues tokens valid for an hour and answers item lists only
+// with a Bearer token.
+func newAuthServer(t *testing.T, now func() time.Time) (*httptest.Server, *authCounters) {
+	t.Helper()
+
+	var counters authCounters
+	token := func(n int32) map[string]any {
+		refreshIn := counters.refreshIn.Load()
+		if refreshIn == 0 {
+			refreshIn = 3600
+		}
+		return map[string]any{
+			"access_token":       fmt.Sprint("access-", n),
+			"refresh_token":      fmt.Sprint("refresh-", n),
+			"expires_at":         now().Add(time.Hour).UTC().Format("2006-01-02 15:04:05"),
+			"refresh_in_seconds": refreshIn,
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v2/login", func(w http.ResponseWriter, r *http.Request) {
+		n := counters.logins.Add(1)
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"token": token(n)}})
+	})
+	mux.HandleFunc("PUT /v2/refresh", func(w http.ResponseWriter, r *http.Request) {
+		if counters.failRefresh.Load() {
+			counters.failedRefreshes.Add(1)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		n := counters.refreshes.Add(1)
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"token": token(100 + n)}})
+	})
+	mux.HandleFunc("GET /v2/items/houses", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-KEY") != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if revoked, _ := counters.revoked.Load().(string); revoked != "" && r.Header.Get("Authorization") == "Bearer "+revoked {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"data":{"data":{"response":[{"authorization":"` + r.Header.Get("Authorization") + `"}]}}}`))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server, &counters
+}
+
+func TestLoginTokenSource(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	clock := func() time.Time { return now }
+
+	server, counters := newAuthServer(t, clock)
+	sdk := New(&Config{BaseURL: server.URL, BaseAuthUrl: server.URL, AppId: "app"})
+
+	ts := NewTokenSource(sdk, map[string]any{"username": "john", "password": "secret"})
+	ts.now = clock
+
+	t.Run("logs in once", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				token, err := ts.Token(ctx)
+				assert.NoError(t, err)
+				assert.Equal(t, "access-1", token.AccessToken)
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), counters.logins.Load())
+	})
+
+	t.Run("refreshes before expiry", func(t *testing.T) {
+		now = now.Add(59*time.Minute + 30*time.Second)
+
+		token, err := ts.Token(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "access-101", token.AccessToken)
+		assert.Equal(t, int32(1), counters.refreshes.Load())
+		assert.Equal(t, int32(1), counters.logins.Load())
+	})
+
+	t.Run("keeps a valid token when refresh fails", func(t *testing.T) {
+		counters.failRefresh.Store(true)
+		now = now.Add(59*time.Minute + 30*time.Second)
+
+		token, err := ts.Token(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "access-101", token.AccessToken)
+	})
+
+	t.Run("logs in again once expired", func(t *testing.T) {
+		now = now.Add(time.Hour)
+
+		token, err := ts.Token(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "access-2", token.AccessToken)
+		assert.Equal(t, int32(2), counters.logins.Load())
+	})
+
+	t.Run("calls carry the bearer token", func(t *testing.T) {
+		list, _, err := WithTokenSource(sdk, ts).Items("houses").GetList().Exec()
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer access-2", list.Data.Data.Response[0]["authorization"])
+
+		_, _, err = sdk.Items("houses").GetList().Exec()
+		assert.ErrorIs(t, err, ErrBadRequest)
+	})
+}
+
+func TestLoginTokenSourceShortLifetime(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	clock := func() time.Time { return now }
+
+	server, counters := newAuthServer(t, clock)
+	counters.refreshIn.Store(30)
+	sdk := New(&Config{BaseURL: server.URL, BaseAuthUrl: server.URL, AppId: "app"})
+
+	ts := NewTokenSource(sdk, map[string]any{"username": "john", "password": "secret"})
+	ts.now = clock
+
+	t.Run("refreshes half way through", func(t *testing.T) {
+		for range 5 {
+			_, err := ts.Token(ctx)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, int32(0), counters.refreshes.Load())
+
+		now = now.Add(14 * time.Second)
+		_, err := ts.Token(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int32(0), counters.refreshes.Load())
+
+		now = now.Add(time.Second)
+		for range 5 {
+			token, err := ts.Token(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, "access-101", token.AccessToken)
+		}
+		assert.Equal(t, int32(1), counters.refreshes.Load())
+	})
+
+	t.Run("waits before retrying a failed refresh", func(t *testing.T) {
+		counters.failRefresh.Store(true)
+		now = now.Add(15 * time.Second)
+
+		for range 5 {
+			token, err := ts.Token(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, "access-101", token.AccessToken)
+		}
+		assert.Equal(t, int32(1), counters.failedRefreshes.Load())
+
+		now = now.Add(refreshRetryDelay)
+		_, err := ts.Token(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), counters.failedRefreshes.Load())
+		assert.Equal(t, int32(1), counters.logins.Load())
+	})
+}
+
+func TestRefreshingTokenSourceUnknownExpiry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	clock := func() time.Time { return now }
+
+	server, counters := newAuthServer(t, clock)
+	sdk := New(&Config{BaseURL: server.URL, BaseAuthUrl: server.URL, AppId: "app"})
+
+	ts := RefreshingTokenSource(sdk, &Token{AccessToken: "access-0", RefreshToken: "refresh-0", RefreshInSeconds: 30})
+	ts.now = clock
+
+	t.Run("keeps the token when refresh fails", func(t *testing.T) {
+		counters.failRefresh.Store(true)
+		now = now.Add(time.Minute)
+
+		for range 3 {
+			token, err := ts.Token(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, "access-0", token.AccessToken)
+		}
+		assert.Equal(t, int32(1), counters.failedRefreshes.Load())
+
+		now = now.Add(refreshRetryDelay)
+		_, err := ts.Token(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), counters.failedRefreshes.Load())
+	})
+
+	t.Run("refreshes a token the API rejects", func(t *testing.T) {
+		counters.failRefresh.Store(false)
+		counters.revoked.Store("access-0")
+		// Back off after the failed refreshes, the token is not due.
+		now = now.Add(time.Second)
+
+		list, _, err := WithTokenSource(sdk, ts).Items("houses").GetList().Exec()
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer access-101", list.Data.Data.Response[0]["authorization"])
+		assert.Equal(t, int32(1), counters.refreshes.Load())
+	})
+
+	t.Run("fails once the rejected token cannot be refreshed", func(t *testing.T) {
+		counters.failRefresh.Store(true)
+		counters.revoked.Store("access-101")
+
+		_, _, err := WithTokenSource(sdk, ts).Items("houses").GetList().Exec()
+		assert.ErrorIs(t, err, ErrUnauthorized)
+
+		_, err = ts.Token(ctx)
+		assert.ErrorIs(t, err, ErrTokenExpired)
+	})
+}
+
+func TestLoginTokenSourceCanceledWaiter(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.Write([]byte(`{"data":{"token":{"access_token":"access-1","refresh_token":"refresh-1"}}}`))
+	}))
+	defer server.Close()
+
+	sdk := New(&Config{BaseURL: server.URL, BaseAuthUrl: server.URL})
+	ts := RefreshingTokenSource(sdk, &Token{AccessToken: "access-0", RefreshToken: "refresh-0"})
+	ts.Invalidate(&Token{AccessToken: "access-0"})
+
+	first := make(chan *Token)
+	go func() {
+		token, err := ts.Token(context.Background())
+		assert.NoError(t, err)
+		first <- token
+	}()
+	<-started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := ts.Token(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	close(release)
+	assert.Equal(t, "access-1", (<-first).AccessToken)
+
+	token, err := ts.Token(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "access-1", token.AccessToken)
+}
+
+func TestParseTokenTime(t *testing.T) {
+	expected := time.Date(2024, 10, 7, 12, 30, 0, 0, time.UTC)
+	assert.Equal(t, expected, parseTokenTime("2024-10-07T12:30:00Z"))
+	assert.Equal(t, expected, parseTokenTime("2024-10-07 12:30:00"))
+	assert.True(t, parseTokenTime("").IsZero())
+}
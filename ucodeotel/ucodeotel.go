@@ -0,0 +1,243 @@
+/*
+Package ucodeotel instruments the SDK with OpenTelemetry. It starts an
+internal span for every Exec, with a child client span for each of its
+attempts, propagates the trace context to u-code in the W3C traceparent
+header and records request counts and latencies.
+
+	ucodeotel.Instrument(cfg)
+	sdk := ucodesdk.New(cfg)
+
+The global tracer provider, meter provider and propagator are used unless
+set with the options.
+*/
+package ucodeotel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	ucodesdk "github.com/ucode-io/ucode_sdk"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of the spans and metrics.
+const ScopeName = "github.com/ucode-io/ucode_sdk/ucodeotel"
+
+// Attributes set on the spans and metrics besides the HTTP semantic
+// conventions.
+const (
+	OperationKey  = attribute.Key("ucode.operation")
+	CollectionKey = attribute.Key("ucode.collection")
+	FromOFSKey    = attribute.Key("ucode.from_ofs")
+	// LimiterWaitKey is the time in seconds the call waited for the
+	// Config.Limiter of its client.
+	LimiterWaitKey = attribute.Key("ucode.limiter.wait")
+)
+
+// Option configures Middleware.
+type Option func(*config)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	propagators    propagation.TextMapPropagator
+}
+
+// WithTracerProvider sets the provider of the tracer creating the spans.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) { c.tracerProvider = provider }
+}
+
+// WithMeterProvider sets the provider of the meter recording the metrics.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) { c.meterProvider = provider }
+}
+
+// WithPropagators sets the propagator injecting the trace context into the
+// headers of the calls.
+func WithPropagators(propagators propagation.TextMapPropagator) Option {
+	return func(c *config) { c.propagators = propagators }
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+		propagators:    otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// Instrument adds ExecMiddleware and Middleware to cfg.
+func Instrument(cfg *ucodesdk.Config, opts ...Option) {
+	cfg.ExecMiddleware = append(cfg.ExecMiddleware, ExecMiddleware(opts...))
+	cfg.Middleware = append(cfg.Middleware, Middleware(opts...))
+}
+
+// ExecMiddleware returns the middleware starting an internal span for every
+// Exec, to be set on Config.ExecMiddleware. It covers the retries of the
+// call; the client spans of Middleware for its attempts are its children.
+func ExecMiddleware(opts ...Option) ucodesdk.Middleware {
+	cfg := newConfig(opts)
+	tracer := cfg.tracerProvider.Tracer(ScopeName)
+
+	return func(next ucodesdk.RoundTripFunc) ucodesdk.RoundTripFunc {
+		return func(ctx context.Context, call *ucodesdk.Call) (*ucodesdk.Result, error) {
+			ctx, span := tracer.Start(ctx, spanName(call), trace.WithSpanKind(trace.SpanKindInternal), trace.WithAttributes(callAttributes(call)...))
+			defer span.End()
+
+			result, err := next(ctx, call)
+			span.SetAttributes(outcomeAttributes(span, call, result, err)...)
+
+			return result, err
+		}
+	}
+}
+
+// Middleware returns the middleware instrumenting every attempt of a call
+// with a span, a child of the span of ExecMiddleware when it is set too.
+// Retries carry http.request.resend_count.
+//
+// It records three metrics:
+//
+//   - ucode.client.requests, the number of attempts;
+//   - ucode.client.duration, their duration in seconds;
+//   - ucode.client.limiter.wait, the time they waited for the limiter.
+func Middleware(opts ...Option) ucodesdk.Middleware {
+	cfg := newConfig(opts)
+
+	tracer := cfg.tracerProvider.Tracer(ScopeName)
+	meter := cfg.meterProvider.Meter(ScopeName)
+
+	requests, err := meter.Int64Counter("ucode.client.requests",
+		metric.WithDescription("Number of calls to the u-code API."),
+		metric.WithUnit("{request}"))
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	duration, err := meter.Float64Histogram("ucode.client.duration",
+		metric.WithDescription("Duration of the calls to the u-code API."),
+		metric.WithUnit("s"))
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	limiterWait, err := meter.Float64Histogram("ucode.client.limiter.wait",
+		metric.WithDescription("Time the calls to the u-code API waited for the client-side limiter."),
+		metric.WithUnit("s"))
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	return func(next ucodesdk.RoundTripFunc) ucodesdk.RoundTripFunc {
+		return func(ctx context.Context, call *ucodesdk.Call) (*ucodesdk.Result, error) {
+			attrs := callAttributes(call)
+
+			ctx, span := tracer.Start(ctx, spanName(call), trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+			defer span.End()
+			if call.Attempt > 1 {
+				span.SetAttributes(semconv.HTTPRequestResendCount(call.Attempt - 1))
+			}
+			if call.Wait > 0 {
+				span.SetAttributes(LimiterWaitKey.Float64(call.Wait.Seconds()))
+				if limiterWait != nil {
+					limiterWait.Record(ctx, call.Wait.Seconds(), metric.WithAttributeSet(attribute.NewSet(attrs...)))
+				}
+			}
+
+			if call.Headers == nil {
+				call.Headers = map[string]string{}
+			}
+			cfg.propagators.Inject(ctx, propagation.MapCarrier(call.Headers))
+
+			start := time.Now()
+			result, err := next(ctx, call)
+			elapsed := time.Since(start).Seconds()
+
+			outcome := outcomeAttributes(span, call, result, err)
+			span.SetAttributes(outcome...)
+
+			set := metric.WithAttributeSet(attribute.NewSet(append(attrs, outcome...)...))
+			if requests != nil {
+				requests.Add(ctx, 1, set)
+			}
+			if duration != nil {
+				duration.Record(ctx, elapsed, set)
+			}
+
+			return result, err
+		}
+	}
+}
+
+func spanName(call *ucodesdk.Call) string {
+	if call.Operation == "" {
+		return call.Method
+	}
+	return call.Operation
+}
+
+// outcomeAttributes describes the result of call and records its error on
+// span. The error message is redacted like the records of Config.Logger, as
+// it carries the URL of the call.
+func outcomeAttributes(span trace.Span, call *ucodesdk.Call, result *ucodesdk.Result, err error) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if result != nil {
+		attrs = append(attrs, semconv.HTTPResponseStatusCode(result.StatusCode))
+	}
+	if err != nil {
+		attrs = append(attrs, semconv.ErrorTypeKey.String(errorType(err)))
+
+		message := ucodesdk.RedactError(call, err)
+		span.AddEvent(semconv.ExceptionEventName, trace.WithAttributes(
+			semconv.ExceptionType(fmt.Sprintf("%T", err)),
+			semconv.ExceptionMessage(message),
+		))
+		span.SetStatus(codes.Error, message)
+	}
+	return attrs
+}
+
+// callAttributes describes call before it is sent.
+func callAttributes(call *ucodesdk.Call) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		OperationKey.String(call.Operation),
+		semconv.HTTPRequestMethodKey.String(call.Method),
+	}
+	if call.Collection != "" {
+		attrs = append(attrs, CollectionKey.String(call.Collection))
+	}
+
+	if u, err := url.Parse(call.URL); err == nil {
+		attrs = append(attrs, semconv.ServerAddress(u.Hostname()))
+		if fromOFS, err := strconv.ParseBool(u.Query().Get("from-ofs")); err == nil {
+			attrs = append(attrs, FromOFSKey.Bool(fromOFS))
+		}
+	}
+
+	return attrs
+}
+
+// errorType is the status code of API errors and "_OTHER" for the others,
+// as recommended by the HTTP semantic conventions.
+func errorType(err error) string {
+	var apiErr *ucodesdk.APIError
+	if errors.As(err, &apiErr) {
+		return strconv.Itoa(apiErr.StatusCode)
+	}
+	return "_OTHER"
+}
@@ -0,0 +1,178 @@
+package ucodeotel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ucodesdk "github.com/ucode-io/ucode_sdk"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func value(span sdktrace.ReadOnlySpan, key attribute.Key) (attribute.Value, bool) {
+	set := attribute.NewSet(span.Attributes()...)
+	return set.Value(key)
+}
+
+func TestMiddleware(t *testing.T) {
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("Traceparent")
+		if r.URL.Path == "/v2/items/missing/1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	spans := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	sdk := ucodesdk.New(&ucodesdk.Config{
+		BaseURL: server.URL,
+		AppId:   "app",
+		Middleware: []ucodesdk.Middleware{Middleware(
+			WithTracerProvider(tracerProvider),
+			WithMeterProvider(meterProvider),
+			WithPropagators(propagation.TraceContext{}),
+		)},
+	})
+
+	ctx, parent := tracerProvider.Tracer("test").Start(context.Background(), "handler")
+	_, _, err := sdk.Items("houses").GetList().ExecContext(ctx)
+	require.NoError(t, err)
+	_, _, err = sdk.Items("missing").GetSingle("1").ExecContext(ctx)
+	require.Error(t, err)
+	parent.End()
+
+	ended := spans.Ended()
+	require.Len(t, ended, 3)
+
+	list := ended[0]
+	assert.Equal(t, ucodesdk.OpItemsGetList, list.Name())
+	assert.Equal(t, trace.SpanKindClient, list.SpanKind())
+	assert.Equal(t, parent.SpanContext().TraceID(), list.SpanContext().TraceID())
+	assert.Equal(t, parent.SpanContext().SpanID(), list.Parent().SpanID())
+
+	for key, want := range map[attribute.Key]attribute.Value{
+		OperationKey:                attribute.StringValue(ucodesdk.OpItemsGetList),
+		CollectionKey:               attribute.StringValue("houses"),
+		FromOFSKey:                  attribute.BoolValue(true),
+		"http.request.method":       attribute.StringValue(http.MethodGet),
+		"http.response.status_code": attribute.IntValue(http.StatusOK),
+	} {
+		got, ok := value(list, key)
+		assert.True(t, ok, key)
+		assert.Equal(t, want, got, key)
+	}
+
+	missing := ended[1]
+	assert.Equal(t, codes.Error, missing.Status().Code)
+	errorType, _ := value(missing, "error.type")
+	assert.Equal(t, "404", errorType.AsString())
+	assert.Contains(t, traceparent, missing.SpanContext().SpanID().String())
+
+	var metrics metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &metrics))
+	require.Len(t, metrics.ScopeMetrics, 1)
+	assert.Equal(t, ScopeName, metrics.ScopeMetrics[0].Scope.Name)
+
+	byName := map[string]metricdata.Aggregation{}
+	for _, m := range metrics.ScopeMetrics[0].Metrics {
+		byName[m.Name] = m.Data
+	}
+
+	requests := byName["ucode.client.requests"].(metricdata.Sum[int64])
+	require.Len(t, requests.DataPoints, 2)
+	var total int64
+	for _, point := range requests.DataPoints {
+		total += point.Value
+	}
+	assert.Equal(t, int64(2), total)
+
+	duration := byName["ucode.client.duration"].(metricdata.Histogram[float64])
+	require.Len(t, duration.DataPoints, 2)
+	assert.Equal(t, uint64(1), duration.DataPoints[0].Count)
+}
+
+func TestInstrumentRetries(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts++; attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	spans := tracetest.NewSpanRecorder()
+	policy := ucodesdk.DefaultRetryPolicy()
+	policy.InitialBackoff = 0
+
+	cfg := &ucodesdk.Config{BaseURL: server.URL, Retry: policy}
+	Instrument(cfg, WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))))
+	sdk := ucodesdk.New(cfg)
+
+	_, err := sdk.Items("houses").Delete().Single("1").Exec()
+	require.NoError(t, err)
+
+	ended := spans.Ended()
+	require.Len(t, ended, 3)
+
+	first, second, exec := ended[0], ended[1], ended[2]
+	assert.Equal(t, ucodesdk.OpItemsDelete, exec.Name())
+	assert.Equal(t, trace.SpanKindInternal, exec.SpanKind())
+	assert.False(t, exec.Parent().IsValid())
+	status, _ := value(exec, "http.response.status_code")
+	assert.Equal(t, int64(http.StatusOK), status.AsInt64())
+
+	for _, attempt := range []sdktrace.ReadOnlySpan{first, second} {
+		assert.Equal(t, trace.SpanKindClient, attempt.SpanKind())
+		assert.Equal(t, exec.SpanContext().TraceID(), attempt.SpanContext().TraceID())
+		assert.Equal(t, exec.SpanContext().SpanID(), attempt.Parent().SpanID())
+	}
+	assert.Equal(t, codes.Error, first.Status().Code)
+	_, ok := value(first, "http.request.resend_count")
+	assert.False(t, ok)
+	resends, _ := value(second, "http.request.resend_count")
+	assert.Equal(t, int64(1), resends.AsInt64())
+}
+
+func TestMiddlewareRedactsErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	spans := tracetest.NewSpanRecorder()
+	cfg := &ucodesdk.Config{BaseURL: server.URL, BaseAuthUrl: server.URL}
+	Instrument(cfg, WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))))
+
+	_, _, err := ucodesdk.New(cfg).Auth().VerifyOTP(ucodesdk.VerifyOTPRequest{SmsId: "sms-1", Otp: "987654"}).Exec()
+	require.Error(t, err)
+
+	ended := spans.Ended()
+	require.Len(t, ended, 2)
+	for _, span := range ended {
+		assert.Contains(t, span.Status().Description, "/v2/verify/sms-1")
+		assert.NotContains(t, span.Status().Description, "987654")
+		require.Len(t, span.Events(), 1)
+		for _, attr := range span.Events()[0].Attributes {
+			assert.NotContains(t, attr.Value.Emit(), "987654", attr.Key)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Package ucodeprom exposes the calls of the SDK as Prometheus metrics.
+
+	collector := ucodeprom.NewCollector(ucodeprom.Opts{})
+	prometheus.MustRegister(collector)
+
+	cfg.Middleware = append(cfg.Middleware, collector.Middleware())
+	sdk := ucodesdk.New(cfg)
+
+Every metric is labelled by the operation of the call, e.g. items_create or
+auth_login, and the collection it targets; the counter and histogram also by
+its outcome. The collector sees the HTTP responses, not the decoding done by
+the Exec methods afterwards, so a response the SDK fails to decode is
+counted as ok.
+*/
+package ucodeprom
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ucodesdk "github.com/ucode-io/ucode_sdk"
+)
+
+// Outcomes of a call.
+const (
+	OutcomeOK             = "ok"
+	OutcomeHTTPError      = "http_error"
+	OutcomeTransportError = "transport_error"
+)
+
+// Opts configures a Collector.
+type Opts struct {
+	// Namespace prefixes the metric names, e.g. "billing" gives
+	// billing_ucode_client_requests_total.
+	Namespace string
+	// Buckets of the duration and limiter wait histograms. Defaults to
+	// prometheus.DefBuckets.
+	Buckets     []float64
+	ConstLabels prometheus.Labels
+}
+
+// Collector collects the metrics of the calls passing through its
+// Middleware:
+//
+//   - ucode_client_requests_total, the number of calls;
+//   - ucode_client_request_duration_seconds, their duration;
+//   - ucode_client_in_flight_requests, the calls waiting for a response;
+//   - ucode_client_limiter_wait_seconds, the time calls waited for the
+//     Config.Limiter of their client.
+type Collector struct {
+	requests    *prometheus.CounterVec
+	duration    *prometheus.HistogramVec
+	inFlight    *prometheus.GaugeVec
+	limiterWait *prometheus.HistogramVec
+}
+
+// NewCollector returns a collector to register with a Prometheus registry.
+func NewCollector(opts Opts) *Collector {
+	buckets := opts.Buckets
+	if buckets == nil {
+		buckets = prometheus.DefBuckets
+	}
+
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   opts.Namespace,
+			Subsystem:   "ucode_client",
+			Name:        "requests_total",
+			Help:        "Number of calls to the u-code API.",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"operation", "collection", "outcome"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   opts.Namespace,
+			Subsystem:   "ucode_client",
+			Name:        "request_duration_seconds",
+			Help:        "Duration of the calls to the u-code API.",
+			Buckets:     buckets,
+			ConstLabels: opts.ConstLabels,
+		}, []string{"operation", "collection", "outcome"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   opts.Namespace,
+			Subsystem:   "ucode_client",
+			Name:        "in_flight_requests",
+			Help:        "Number of calls to the u-code API waiting for a response.",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"operation", "collection"}),
+		limiterWait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   opts.Namespace,
+			Subsystem:   "ucode_client",
+			Name:        "limiter_wait_seconds",
+			Help:        "Time the calls to the u-code API waited for the client-side limiter.",
+			Buckets:     buckets,
+			ConstLabels: opts.ConstLabels,
+		}, []string{"operation", "collection"}),
+	}
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.duration.Describe(ch)
+	c.inFlight.Describe(ch)
+	c.limiterWait.Describe(ch)
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.duration.Collect(ch)
+	c.inFlight.Collect(ch)
+	c.limiterWait.Collect(ch)
+}
+
+// Middleware returns the middleware recording every call into c. Each
+// attempt of a retried call is counted.
+func (c *Collector) Middleware() ucodesdk.Middleware {
+	return func(next ucodesdk.RoundTripFunc) ucodesdk.RoundTripFunc {
+		return func(ctx context.Context, call *ucodesdk.Call) (*ucodesdk.Result, error) {
+			if call.Wait > 0 {
+				c.limiterWait.WithLabelValues(call.Operation, call.Collection).Observe(call.Wait.Seconds())
+			}
+
+			inFlight := c.inFlight.WithLabelValues(call.Operation, call.Collection)
+			inFlight.Inc()
+			defer inFlight.Dec()
+
+			start := time.Now()
+			result, err := next(ctx, call)
+
+			outcome := Outcome(result, err)
+			c.requests.WithLabelValues(call.Operation, call.Collection, outcome).Inc()
+			c.duration.WithLabelValues(call.Operation, call.Collection, outcome).Observe(time.Since(start).Seconds())
+
+			return result, err
+		}
+	}
+}
+
+// Outcome classifies a call: an HTTP error for responses with a status of
+// 400 or above, a transport error for any other error, such as no response
+// being received, and ok otherwise.
+func Outcome(result *ucodesdk.Result, err error) string {
+	var apiErr *ucodesdk.APIError
+	switch {
+	case errors.As(err, &apiErr):
+		return OutcomeHTTPError
+	case err != nil:
+		return OutcomeTransportError
+	default:
+		return OutcomeOK
+	}
+}
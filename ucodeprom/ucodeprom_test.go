@@ -0,0 +1,76 @@
+package ucodeprom
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ucodesdk "github.com/ucode-io/ucode_sdk"
+)
+
+func TestCollector(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/v2/items/missing"):
+			w.WriteHeader(http.StatusNotFound)
+		case strings.HasPrefix(r.URL.Path, "/v1/invoke_function"):
+			w.Write([]byte(`<html>`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	collector := NewCollector(Opts{Namespace: "test"})
+	registry := prometheus.NewPedanticRegistry()
+	require.NoError(t, registry.Register(collector))
+
+	sdk := ucodesdk.New(&ucodesdk.Config{
+		BaseURL:     server.URL,
+		BaseAuthUrl: server.URL,
+		Middleware:  []ucodesdk.Middleware{collector.Middleware()},
+		Limiter:     ucodesdk.NewLimiter(ucodesdk.Limits{MaxInFlight: 4}),
+	})
+
+	sdk.Items("houses").Create(map[string]any{}).Exec()
+	sdk.Items("houses").GetList().Exec()
+	sdk.Items("houses").GetList().Exec()
+	sdk.Items("missing").GetList().Exec()
+	sdk.Function("notify").Invoke(nil).Exec()
+	sdk.Auth().Login(map[string]any{}).Exec()
+
+	broken := ucodesdk.New(&ucodesdk.Config{BaseURL: "http://127.0.0.1:0", Middleware: []ucodesdk.Middleware{collector.Middleware()}})
+	broken.Items("houses").GetList().Exec()
+
+	for labels, want := range map[[3]string]float64{
+		{ucodesdk.OpItemsCreate, "houses", OutcomeOK}:              1,
+		{ucodesdk.OpItemsGetList, "houses", OutcomeOK}:             2,
+		{ucodesdk.OpItemsGetList, "missing", OutcomeHTTPError}:     1,
+		{ucodesdk.OpItemsGetList, "houses", OutcomeTransportError}: 1,
+		{ucodesdk.OpFunctionInvoke, "notify", OutcomeOK}:           1,
+		{ucodesdk.OpAuthLogin, "", OutcomeOK}:                      1,
+	} {
+		assert.Equal(t, want, testutil.ToFloat64(collector.requests.WithLabelValues(labels[:]...)), labels)
+	}
+
+	assert.Equal(t, 6, testutil.CollectAndCount(collector, "test_ucode_client_request_duration_seconds"))
+	assert.Zero(t, testutil.ToFloat64(collector.inFlight.WithLabelValues(ucodesdk.OpItemsGetList, "houses")))
+	assert.Equal(t, 5, testutil.CollectAndCount(collector, "test_ucode_client_limiter_wait_seconds"))
+
+	problems, err := testutil.CollectAndLint(collector)
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+}
+
+func TestOutcome(t *testing.T) {
+	assert.Equal(t, OutcomeOK, Outcome(&ucodesdk.Result{StatusCode: http.StatusNoContent}, nil))
+	assert.Equal(t, OutcomeTransportError, Outcome(nil, errors.New("connection reset")))
+	assert.Equal(t, OutcomeHTTPError, Outcome(&ucodesdk.Result{StatusCode: http.StatusBadGateway}, &ucodesdk.APIError{StatusCode: http.StatusBadGateway}))
+	assert.Equal(t, OutcomeOK, Outcome(&ucodesdk.Result{StatusCode: http.StatusOK, Body: []byte("oops")}, nil))
+}
@@ -0,0 +1,512 @@
+package ucodetest
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+// tokenLifetime is the validity of the access tokens issued by the server.
+const tokenLifetime = time.Hour
+
+type user struct {
+	Id           string
+	Login        string
+	Password     string
+	Email        string
+	Phone        string
+	Name         string
+	RoleId       string
+	ClientTypeId string
+	Data         map[string]any
+}
+
+type session struct {
+	id           string
+	userId       string
+	accessToken  string
+	refreshToken string
+	createdAt    time.Time
+	expiresAt    time.Time
+}
+
+// AddUser registers a user that can log in with login and password, and
+// returns its id.
+func (s *Server) AddUser(login, password string, data map[string]any) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addUser(login, password, data).Id
+}
+
+func (s *Server) addUser(login, password string, data map[string]any) *user {
+	u := &user{
+		Id:           newID(),
+		Login:        login,
+		Password:     password,
+		Email:        cast.ToString(data["email"]),
+		Phone:        cast.ToString(data["phone"]),
+		Name:         cast.ToString(data["name"]),
+		RoleId:       cast.ToString(data["role_id"]),
+		ClientTypeId: cast.ToString(data["client_type_id"]),
+		Data:         data,
+	}
+	s.users = append(s.users, u)
+
+	return u
+}
+
+func (s *Server) findUser(match func(*user) bool) *user {
+	for _, u := range s.users {
+		if match(u) {
+			return u
+		}
+	}
+	return nil
+}
+
+// issueToken creates a session for u. It must be called with s.mu held.
+func (s *Server) issueToken(u *user) map[string]any {
+	ses := &session{id: newID(), userId: u.Id, createdAt: time.Now().UTC()}
+	s.sessions[ses.id] = ses
+
+	return s.renew(ses)
+}
+
+// renew gives ses new tokens and returns them. It must be called with s.mu
+// held.
+func (s *Server) renew(ses *session) map[string]any {
+	now := time.Now().UTC()
+
+	ses.accessToken = newID()
+	ses.refreshToken = newID()
+	ses.expiresAt = now.Add(tokenLifetime)
+
+	return map[string]any{
+		"access_token":       ses.accessToken,
+		"refresh_token":      ses.refreshToken,
+		"created_at":         ses.createdAt.Format(time.RFC3339),
+		"updated_at":         now.Format(time.RFC3339),
+		"expires_at":         ses.expiresAt.Format(time.RFC3339),
+		"refresh_in_seconds": int(tokenLifetime.Seconds()),
+	}
+}
+
+// findSession returns the session matching match. It must be called with
+// s.mu held.
+func (s *Server) findSession(match func(*session) bool) *session {
+	for _, ses := range s.sessions {
+		if match(ses) {
+			return ses
+		}
+	}
+	return nil
+}
+
+func (s *Server) validToken(authorization string) bool {
+	token, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ses := s.findSession(func(ses *session) bool { return ses.accessToken == token })
+	return ses != nil && time.Now().Before(ses.expiresAt)
+}
+
+func (ses *session) json() map[string]any {
+	return map[string]any{
+		"id":         ses.id,
+		"user_id":    ses.userId,
+		"created_at": ses.createdAt.Format(time.RFC3339),
+		"updated_at": ses.createdAt.Format(time.RFC3339),
+		"expires_at": ses.expiresAt.Format(time.RFC3339),
+	}
+}
+
+func (u *user) json() map[string]any {
+	return map[string]any{
+		"id":             u.Id,
+		"login":          u.Login,
+		"email":          u.Email,
+		"phone":          u.Phone,
+		"name":           u.Name,
+		"role_id":        u.RoleId,
+		"client_type_id": u.ClientTypeId,
+	}
+}
+
+// loginData builds the data of a LoginResponse. It must be called with
+// s.mu held.
+func (s *Server) loginData(u *user) map[string]any {
+	data := u.identity()
+	data["token"] = s.issueToken(u)
+
+	return data
+}
+
+// identity returns the fields of a LoginResponse describing u.
+func (u *user) identity() map[string]any {
+	return map[string]any{
+		"user_found":     true,
+		"user_id":        u.Id,
+		"user":           u.json(),
+		"user_data":      u.Data,
+		"role":           map[string]any{"id": u.RoleId},
+		"client_type":    map[string]any{"id": u.ClientTypeId},
+		"environment_id": "",
+		"resource_id":    "",
+	}
+}
+
+func (s *Server) register(w http.ResponseWriter, r *http.Request) {
+	var body map[string]any
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, ok := body["data"].(map[string]any)
+	if !ok {
+		data = body
+	}
+
+	login := cast.ToString(data["login"])
+	if login == "" {
+		login = cast.ToString(data["username"])
+	}
+	if login == "" && data["phone"] == nil && data["email"] == nil {
+		writeError(w, http.StatusBadRequest, "login, phone or email is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.addUser(login, cast.ToString(data["password"]), data)
+
+	writeJSON(w, http.StatusCreated, "CREATED", map[string]any{
+		"user_found": false,
+		"user_id":    u.Id,
+		"token":      s.issueToken(u),
+		"user":       u.json(),
+	})
+}
+
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	var body map[string]any
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	login := cast.ToString(body["username"])
+	if login == "" {
+		login = cast.ToString(body["login"])
+	}
+	password := cast.ToString(body["password"])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.findUser(func(u *user) bool { return u.Login == login && u.Password == password })
+	if u == nil {
+		writeError(w, http.StatusUnauthorized, "invalid login or password")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, "CREATED", s.loginData(u))
+}
+
+func (s *Server) loginWithOption(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Data          map[string]any `json:"data"`
+		LoginStrategy string         `json:"login_strategy"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var u *user
+	switch body.LoginStrategy {
+	case "LOGIN_PWD", "LOGIN":
+		login := cast.ToString(body.Data["username"])
+		if login == "" {
+			login = cast.ToString(body.Data["login"])
+		}
+		password := cast.ToString(body.Data["password"])
+		u = s.findUser(func(u *user) bool { return u.Login == login && u.Password == password })
+	case "PHONE_OTP", "EMAIL_OTP", "PHONE", "EMAIL":
+		field := "phone"
+		if strings.HasPrefix(body.LoginStrategy, "EMAIL") {
+			field = "email"
+		}
+		recipient := cast.ToString(body.Data[field])
+		if s.codes[cast.ToString(body.Data["sms_id"])] != recipient || cast.ToString(body.Data["otp"]) != DefaultOTP {
+			writeError(w, http.StatusUnauthorized, "invalid otp")
+			return
+		}
+		delete(s.codes, cast.ToString(body.Data["sms_id"]))
+
+		u = s.findUser(func(u *user) bool {
+			return (field == "phone" && u.Phone == recipient) || (field == "email" && u.Email == recipient)
+		})
+		if u == nil {
+			u = s.addUser("", "", body.Data)
+		}
+	default:
+		writeError(w, http.StatusBadRequest, "unsupported login strategy")
+		return
+	}
+
+	if u == nil {
+		writeError(w, http.StatusUnauthorized, "invalid credentials")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, "CREATED", s.loginData(u))
+}
+
+func (s *Server) sendCode(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Recipient string `json:"recipient"`
+		Type      string `json:"type"`
+	}
+	if err := decodeBody(r, &body); err != nil || body.Recipient == "" {
+		writeError(w, http.StatusBadRequest, "recipient is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	smsId := newID()
+	s.codes[smsId] = body.Recipient
+
+	found := s.findUser(func(u *user) bool { return u.Phone == body.Recipient || u.Email == body.Recipient })
+
+	writeJSON(w, http.StatusCreated, "CREATED", map[string]any{
+		"sms_id":     smsId,
+		"user_found": found != nil,
+	})
+}
+
+func (s *Server) resetPassword(w http.ResponseWriter, r *http.Request) {
+	var body map[string]any
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userId := cast.ToString(body["user_id"])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.findUser(func(u *user) bool { return u.Id == userId })
+	if u == nil {
+		writeError(w, http.StatusNotFound, "user not found")
+		return
+	}
+	u.Password = cast.ToString(body["password"])
+
+	writeJSON(w, http.StatusOK, "OK", nil)
+}
+
+func (s *Server) refresh(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ses := s.findSession(func(ses *session) bool { return ses.refreshToken == body.RefreshToken })
+	if body.RefreshToken == "" || ses == nil {
+		writeError(w, http.StatusUnauthorized, "invalid refresh token")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "OK", map[string]any{
+		"user_id": ses.userId,
+		"token":   s.renew(ses),
+	})
+}
+
+func (s *Server) logout(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ses := s.findSession(func(ses *session) bool { return ses.refreshToken == body.RefreshToken })
+	if body.RefreshToken == "" || ses == nil {
+		writeError(w, http.StatusNotFound, "session not found")
+		return
+	}
+	delete(s.sessions, ses.id)
+
+	writeJSON(w, http.StatusOK, "OK", nil)
+}
+
+func (s *Server) changePassword(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		UserId      string `json:"user_id"`
+		OldPassword string `json:"old_password"`
+		NewPassword string `json:"new_password"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.findUser(func(u *user) bool { return u.Id == body.UserId })
+	if u == nil {
+		writeError(w, http.StatusNotFound, "user not found")
+		return
+	}
+	if u.Password != body.OldPassword {
+		writeError(w, http.StatusBadRequest, "wrong old password")
+		return
+	}
+	u.Password = body.NewPassword
+
+	writeJSON(w, http.StatusOK, "OK", nil)
+}
+
+func (s *Server) verifyOTP(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	smsId := r.PathValue("sms_id")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	recipient, ok := s.codes[smsId]
+	if !ok || r.PathValue("otp") != DefaultOTP {
+		writeError(w, http.StatusUnauthorized, "invalid otp")
+		return
+	}
+	delete(s.codes, smsId)
+
+	u := s.findUser(func(u *user) bool { return u.Phone == recipient || u.Email == recipient })
+	if u == nil {
+		writeJSON(w, http.StatusOK, "OK", map[string]any{"user_found": false})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "OK", map[string]any{
+		"user_found": true,
+		"user_id":    u.Id,
+		"token":      s.issueToken(u),
+		"user_data":  u.Data,
+	})
+}
+
+func (s *Server) listSessions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	userId := query.Get("user-id")
+
+	offset, err := strconv.Atoi(cmp.Or(query.Get("offset"), "0"))
+	if err != nil || offset < 0 {
+		writeError(w, http.StatusBadRequest, "invalid offset")
+		return
+	}
+	limit := -1
+	if query.Has("limit") {
+		if limit, err = strconv.Atoi(query.Get("limit")); err != nil || limit < 0 {
+			writeError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var matched []*session
+	for _, ses := range s.sessions {
+		if userId == "" || ses.userId == userId {
+			matched = append(matched, ses)
+		}
+	}
+	slices.SortFunc(matched, func(a, b *session) int {
+		return cmp.Or(a.createdAt.Compare(b.createdAt), cmp.Compare(a.id, b.id))
+	})
+
+	count := len(matched)
+	matched = matched[min(offset, count):]
+	if limit >= 0 {
+		matched = matched[:min(limit, len(matched))]
+	}
+
+	sessions := []map[string]any{}
+	for _, ses := range matched {
+		sessions = append(sessions, ses.json())
+	}
+
+	writeJSON(w, http.StatusOK, "OK", map[string]any{"sessions": sessions, "count": count})
+}
+
+func (s *Server) deleteSession(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.sessions[r.PathValue("id")]; !ok {
+		writeError(w, http.StatusNotFound, "session not found")
+		return
+	}
+	delete(s.sessions, r.PathValue("id"))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) hasAccess(w http.ResponseWriter, r *http.Request) {
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ses := s.findSession(func(ses *session) bool { return ses.accessToken == token })
+	if token == "" || ses == nil || !time.Now().Before(ses.expiresAt) {
+		writeError(w, http.StatusUnauthorized, "invalid token")
+		return
+	}
+
+	u := s.findUser(func(u *user) bool { return u.Id == ses.userId })
+	if u == nil {
+		writeError(w, http.StatusUnauthorized, "user not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "OK", u.identity())
+}
@@ -0,0 +1,78 @@
+package ucodetest
+
+import (
+	"io"
+	"net/http"
+)
+
+func (s *Server) uploadFile(w http.ResponseWriter, r *http.Request) {
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "file is required")
+		return
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	stored := File{Id: newID(), Name: header.Filename, Content: content}
+
+	s.mu.Lock()
+	s.files[stored.Id] = stored
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusCreated, "CREATED", map[string]any{
+		"id":                 stored.Id,
+		"title":              stored.Name,
+		"storage":            "Media",
+		"file_name_disk":     stored.Id,
+		"file_name_download": stored.Name,
+		"link":               s.URL + "/v1/files/" + stored.Id,
+		"file_size":          len(content),
+	})
+}
+
+func (s *Server) deleteFile(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	_, ok := s.files[r.PathValue("id")]
+	delete(s.files, r.PathValue("id"))
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "file not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) invokeFunction(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	handler, ok := s.functions[r.PathValue("path")]
+	s.mu.Unlock()
+
+	if !ok {
+		writeJSON(w, http.StatusOK, "done", body.Data)
+		return
+	}
+
+	data, err := handler(body.Data)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "done", data)
+}
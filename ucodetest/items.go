@@ -0,0 +1,444 @@
+package ucodetest
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cast"
+)
+
+// collection keeps the objects of one table in insertion order.
+type collection struct {
+	order   []string
+	objects map[string]map[string]any
+}
+
+func (c *collection) insert(object map[string]any) map[string]any {
+	object = maps.Clone(object)
+	if object == nil {
+		object = map[string]any{}
+	}
+
+	guid := cast.ToString(object["guid"])
+	if guid == "" {
+		guid = newID()
+		object["guid"] = guid
+	}
+
+	if _, ok := c.objects[guid]; !ok {
+		c.order = append(c.order, guid)
+	}
+	c.objects[guid] = object
+
+	return maps.Clone(object)
+}
+
+func (c *collection) update(object map[string]any) (map[string]any, bool) {
+	stored, ok := c.objects[cast.ToString(object["guid"])]
+	if !ok {
+		return nil, false
+	}
+
+	maps.Copy(stored, object)
+	return maps.Clone(stored), true
+}
+
+func (c *collection) delete(guid string) bool {
+	if _, ok := c.objects[guid]; !ok {
+		return false
+	}
+
+	delete(c.objects, guid)
+	c.order = slices.DeleteFunc(c.order, func(id string) bool { return id == guid })
+	return true
+}
+
+func (c *collection) all() []map[string]any {
+	objects := make([]map[string]any, 0, len(c.order))
+	for _, guid := range c.order {
+		objects = append(objects, maps.Clone(c.objects[guid]))
+	}
+	return objects
+}
+
+type actionBody struct {
+	Data map[string]any `json:"data"`
+}
+
+func (s *Server) createItem(w http.ResponseWriter, r *http.Request) {
+	var body actionBody
+	if err := decodeBody(r, &body); err != nil || body.Data == nil {
+		writeError(w, http.StatusBadRequest, "data is required")
+		return
+	}
+
+	s.mu.Lock()
+	created := s.collection(r.PathValue("slug")).insert(body.Data)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusCreated, "CREATED", map[string]any{
+		"table_slug": r.PathValue("slug"),
+		"data":       map[string]any{"data": created},
+	})
+}
+
+func (s *Server) updateItem(w http.ResponseWriter, r *http.Request) {
+	var body actionBody
+	if err := decodeBody(r, &body); err != nil || body.Data == nil {
+		writeError(w, http.StatusBadRequest, "data is required")
+		return
+	}
+
+	s.mu.Lock()
+	updated, ok := s.collection(r.PathValue("slug")).update(body.Data)
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "object not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "OK", map[string]any{
+		"table_slug": r.PathValue("slug"),
+		"data":       updated,
+	})
+}
+
+func (s *Server) updateItems(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Data struct {
+			Objects []map[string]any `json:"objects"`
+		} `json:"data"`
+	}
+	if err := decodeBody(r, &body); err != nil || len(body.Data.Objects) == 0 {
+		writeError(w, http.StatusBadRequest, "objects are required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.collection(r.PathValue("slug"))
+	objects := make([]map[string]any, 0, len(body.Data.Objects))
+	for _, object := range body.Data.Objects {
+		updated, ok := c.update(object)
+		if !ok {
+			updated = c.insert(object)
+		}
+		objects = append(objects, updated)
+	}
+
+	writeJSON(w, http.StatusOK, "OK", map[string]any{"data": map[string]any{"objects": objects}})
+}
+
+func (s *Server) deleteItem(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	ok := s.collection(r.PathValue("slug")).delete(r.PathValue("id"))
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "object not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) deleteItems(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Ids []string `json:"ids"`
+	}
+	if err := decodeBody(r, &body); err != nil || len(body.Ids) == 0 {
+		writeError(w, http.StatusBadRequest, "ids are required")
+		return
+	}
+
+	s.mu.Lock()
+	c := s.collection(r.PathValue("slug"))
+	for _, guid := range body.Ids {
+		c.delete(guid)
+	}
+	s.mu.Unlock()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getSingle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	object, ok := s.collection(r.PathValue("slug")).objects[r.PathValue("id")]
+	object = maps.Clone(object)
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "object not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "OK", map[string]any{"data": map[string]any{"response": object}})
+}
+
+// reservedListKeys are keys of the data query parameter that are options
+// rather than filters.
+var reservedListKeys = map[string]bool{
+	"limit": true, "offset": true, "order": true, "search": true,
+	"view_fields": true, "with_relations": true,
+}
+
+func (s *Server) getList(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	request := map[string]any{}
+	if data := query.Get("data"); data != "" {
+		if err := json.Unmarshal([]byte(data), &request); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid data parameter")
+			return
+		}
+	}
+
+	offset, err := strconv.Atoi(cmp.Or(query.Get("offset"), "0"))
+	if err != nil || offset < 0 {
+		writeError(w, http.StatusBadRequest, "invalid offset")
+		return
+	}
+	limit, err := strconv.Atoi(cmp.Or(query.Get("limit"), "10"))
+	if err != nil || limit < 0 {
+		writeError(w, http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	s.mu.Lock()
+	objects := s.collection(r.PathValue("slug")).all()
+	s.mu.Unlock()
+
+	filter := map[string]any{}
+	for key, value := range request {
+		if !reservedListKeys[key] {
+			filter[key] = value
+		}
+	}
+
+	search := strings.ToLower(cast.ToString(request["search"]))
+	objects = slices.DeleteFunc(objects, func(object map[string]any) bool {
+		return !matches(object, filter) || (search != "" && !containsText(object, search))
+	})
+
+	if order, ok := request["order"].(map[string]any); ok {
+		sortObjects(objects, order)
+	}
+
+	count := len(objects)
+	objects = objects[min(offset, count):min(offset+limit, count)]
+
+	writeJSON(w, http.StatusOK, "OK", map[string]any{"data": map[string]any{"count": count, "response": objects}})
+}
+
+// aggregation supports the $match, $sort, $skip and $limit stages.
+func (s *Server) aggregation(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Data struct {
+			Pipelines []map[string]any `json:"pipelines"`
+		} `json:"data"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	objects := s.collection(r.PathValue("slug")).all()
+	s.mu.Unlock()
+
+	for _, stage := range body.Data.Pipelines {
+		for name, value := range stage {
+			switch name {
+			case "$match":
+				filter, _ := value.(map[string]any)
+				objects = slices.DeleteFunc(objects, func(object map[string]any) bool { return !matches(object, filter) })
+			case "$sort":
+				order, _ := value.(map[string]any)
+				sortObjects(objects, order)
+			case "$skip", "$limit":
+				n, err := cast.ToIntE(value)
+				if err != nil || n < 0 {
+					writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid %s", name))
+					return
+				}
+				if name == "$skip" {
+					objects = objects[min(n, len(objects)):]
+				} else {
+					objects = objects[:min(n, len(objects))]
+				}
+			default:
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported stage %s", name))
+				return
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, "OK", map[string]any{"data": map[string]any{"data": objects}})
+}
+
+// matches evaluates a u-code filter against object. It understands plain
+// equality, arrays (any of), dotted paths, $and/$or and the comparison
+// operators produced by the query package.
+func matches(object map[string]any, filter map[string]any) bool {
+	for key, expected := range filter {
+		switch key {
+		case "$and":
+			if !slices.ContainsFunc(toClauses(expected), func(clause map[string]any) bool { return !matches(object, clause) }) {
+				continue
+			}
+			return false
+		case "$or":
+			if slices.ContainsFunc(toClauses(expected), func(clause map[string]any) bool { return matches(object, clause) }) {
+				continue
+			}
+			return false
+		}
+
+		value, exists := lookup(object, key)
+		if !matchValue(value, exists, expected) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func toClauses(value any) []map[string]any {
+	values, _ := value.([]any)
+	clauses := make([]map[string]any, 0, len(values))
+	for _, value := range values {
+		clause, _ := value.(map[string]any)
+		clauses = append(clauses, clause)
+	}
+	return clauses
+}
+
+func matchValue(value any, exists bool, expected any) bool {
+	switch expected := expected.(type) {
+	case map[string]any:
+		if !isOperatorMap(expected) {
+			return equal(value, expected)
+		}
+		for op, operand := range expected {
+			if !matchOperator(value, exists, op, operand) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		return slices.ContainsFunc(expected, func(candidate any) bool { return equal(value, candidate) })
+	default:
+		return equal(value, expected)
+	}
+}
+
+func matchOperator(value any, exists bool, op string, operand any) bool {
+	switch op {
+	case "$eq":
+		return equal(value, operand)
+	case "$ne":
+		return !equal(value, operand)
+	case "$in", "$nin":
+		candidates, _ := operand.([]any)
+		found := slices.ContainsFunc(candidates, func(candidate any) bool { return equal(value, candidate) })
+		return found == (op == "$in")
+	case "$gt":
+		return exists && compare(value, operand) > 0
+	case "$gte":
+		return exists && compare(value, operand) >= 0
+	case "$lt":
+		return exists && compare(value, operand) < 0
+	case "$lte":
+		return exists && compare(value, operand) <= 0
+	case "$regex":
+		re, err := regexp.Compile(cast.ToString(operand))
+		return err == nil && exists && re.MatchString(cast.ToString(value))
+	case "$exists":
+		return (exists && value != nil) == cast.ToBool(operand)
+	}
+
+	return false
+}
+
+func isOperatorMap(m map[string]any) bool {
+	for key := range m {
+		if !strings.HasPrefix(key, "$") {
+			return false
+		}
+	}
+	return len(m) > 0
+}
+
+func lookup(object map[string]any, path string) (any, bool) {
+	var current any = object
+	for _, part := range strings.Split(path, ".") {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func equal(a, b any) bool {
+	if isNumber(a) && isNumber(b) {
+		return cast.ToFloat64(a) == cast.ToFloat64(b)
+	}
+
+	aJSON, _ := json.Marshal(a)
+	bJSON, _ := json.Marshal(b)
+	return string(aJSON) == string(bJSON)
+}
+
+func compare(a, b any) int {
+	if isNumber(a) && isNumber(b) {
+		return cmp.Compare(cast.ToFloat64(a), cast.ToFloat64(b))
+	}
+	return cmp.Compare(cast.ToString(a), cast.ToString(b))
+}
+
+func isNumber(v any) bool {
+	switch v.(type) {
+	case float64, float32, int, int32, int64, json.Number:
+		return true
+	}
+	return false
+}
+
+func containsText(object map[string]any, search string) bool {
+	for _, value := range object {
+		if text, ok := value.(string); ok && strings.Contains(strings.ToLower(text), search) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortObjects(objects []map[string]any, order map[string]any) {
+	fields := slices.Sorted(maps.Keys(order))
+	slices.SortStableFunc(objects, func(a, b map[string]any) int {
+		for _, field := range fields {
+			left, _ := lookup(a, field)
+			right, _ := lookup(b, field)
+			if c := compare(left, right); c != 0 {
+				if cast.ToInt(order[field]) < 0 {
+					return -c
+				}
+				return c
+			}
+		}
+		return 0
+	})
+}
@@ -0,0 +1,238 @@
+/*
+Package ucodetest provides an in-memory fake of the u-code API for offline
+tests.
+
+	func TestOrders(t *testing.T) {
+		server := ucodetest.New(t)
+		server.Seed("orders", map[string]any{"status": "paid"})
+
+		sdk := server.SDK()
+		resp, _, err := sdk.Items("orders").GetList().Exec()
+		...
+	}
+
+The server implements the items, aggregation, files and invoke_function
+routes used by the SDK and the auth routes: register, login, login with
+option, send-code, reset-password, refresh, logout, change-password, verify,
+has-access and listing and deleting sessions. It keeps every object in
+memory.
+*/
+package ucodetest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+
+	ucodesdk "github.com/ucode-io/ucode_sdk"
+)
+
+const (
+	// DefaultAppId is the API key accepted by a server created with New.
+	DefaultAppId = "P-ucodetest"
+	// DefaultProjectId is the project id reported by the server.
+	DefaultProjectId = "ucodetest-project"
+	// DefaultOTP is the code accepted after SendCode.
+	DefaultOTP = "111111"
+)
+
+// FunctionHandler serves /v1/invoke_function/{path}. It receives the data
+// passed to Invoke and returns the data of the response.
+type FunctionHandler func(data map[string]any) (any, error)
+
+// Server is an in-memory u-code API.
+type Server struct {
+	*httptest.Server
+
+	AppId     string
+	ProjectId string
+
+	mu          sync.Mutex
+	collections map[string]*collection
+	files       map[string]File
+	functions   map[string]FunctionHandler
+	users       []*user
+	codes       map[string]string
+	sessions    map[string]*session
+}
+
+// File is an uploaded file kept by the server.
+type File struct {
+	Id      string
+	Name    string
+	Content []byte
+}
+
+// New starts a server that is closed when tb finishes.
+func New(tb testing.TB) *Server {
+	tb.Helper()
+
+	server := NewServer()
+	tb.Cleanup(server.Close)
+
+	return server
+}
+
+// NewServer starts a server. The caller must Close it.
+func NewServer() *Server {
+	s := &Server{
+		AppId:       DefaultAppId,
+		ProjectId:   DefaultProjectId,
+		collections: map[string]*collection{},
+		files:       map[string]File{},
+		functions:   map[string]FunctionHandler{},
+		codes:       map[string]string{},
+		sessions:    map[string]*session{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v2/items/{slug}", s.authorized(s.createItem))
+	mux.HandleFunc("PUT /v2/items/{slug}", s.authorized(s.updateItem))
+	mux.HandleFunc("PATCH /v2/items/{slug}", s.authorized(s.updateItems))
+	mux.HandleFunc("DELETE /v2/items/{slug}", s.authorized(s.deleteItems))
+	mux.HandleFunc("DELETE /v2/items/{slug}/{id}", s.authorized(s.deleteItem))
+	mux.HandleFunc("GET /v2/items/{slug}", s.authorized(s.getList))
+	mux.HandleFunc("GET /v2/items/{slug}/{id}", s.authorized(s.getSingle))
+	mux.HandleFunc("POST /v2/items/{slug}/aggregation", s.authorized(s.aggregation))
+	mux.HandleFunc("POST /v1/files/folder_upload", s.authorized(s.uploadFile))
+	mux.HandleFunc("DELETE /v1/files/{id}", s.authorized(s.deleteFile))
+	mux.HandleFunc("POST /v1/invoke_function/{path}", s.authorized(s.invokeFunction))
+	mux.HandleFunc("POST /v2/register", s.register)
+	mux.HandleFunc("POST /v2/login", s.login)
+	mux.HandleFunc("POST /v2/login/with-option", s.loginWithOption)
+	mux.HandleFunc("POST /v2/send-code", s.sendCode)
+	mux.HandleFunc("PUT /v2/reset-password", s.authorized(s.resetPassword))
+	mux.HandleFunc("PUT /v2/refresh", s.refresh)
+	mux.HandleFunc("POST /v2/logout", s.authorized(s.logout))
+	mux.HandleFunc("PUT /v2/change-password", s.authorized(s.changePassword))
+	mux.HandleFunc("POST /v2/verify/{sms_id}/{otp}", s.verifyOTP)
+	mux.HandleFunc("POST /v2/has-access", s.hasAccess)
+	mux.HandleFunc("GET /v2/sessions", s.authorized(s.listSessions))
+	mux.HandleFunc("DELETE /v2/sessions/{id}", s.authorized(s.deleteSession))
+
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// Config returns a configuration pointing both BaseURL and BaseAuthUrl at
+// the server.
+func (s *Server) Config() *ucodesdk.Config {
+	return &ucodesdk.Config{
+		AppId:       s.AppId,
+		ProjectId:   s.ProjectId,
+		BaseURL:     s.URL,
+		BaseAuthUrl: s.URL,
+	}
+}
+
+// SDK returns a client configured with Config.
+func (s *Server) SDK() ucodesdk.UcodeApis {
+	return ucodesdk.New(s.Config())
+}
+
+// Seed stores objects in collection, assigning a guid to those without one,
+// and returns the stored copies.
+func (s *Server) Seed(collection string, objects ...map[string]any) []map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := make([]map[string]any, 0, len(objects))
+	for _, object := range objects {
+		stored = append(stored, s.collection(collection).insert(object))
+	}
+
+	return stored
+}
+
+// Objects returns a copy of every object of collection in insertion order.
+func (s *Server) Objects(collection string) []map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.collection(collection).all()
+}
+
+// Files returns a copy of every uploaded file.
+func (s *Server) Files() []File {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Collect(maps.Values(s.files))
+}
+
+// HandleFunction registers the handler invoked for path. Unregistered
+// functions echo the data they receive.
+func (s *Server) HandleFunction(path string, handler FunctionHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.functions[path] = handler
+}
+
+func (s *Server) collection(name string) *collection {
+	c, ok := s.collections[name]
+	if !ok {
+		c = &collection{objects: map[string]map[string]any{}}
+		s.collections[name] = c
+	}
+
+	return c
+}
+
+// authorized rejects requests that do not carry the server's API key.
+func (s *Server) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-KEY") != s.AppId && !s.validToken(r.Header.Get("Authorization")) {
+			writeError(w, http.StatusUnauthorized, "invalid api key")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, status string, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]any{
+		"status":         status,
+		"description":    "",
+		"data":           data,
+		"custom_message": "",
+	})
+}
+
+func writeError(w http.ResponseWriter, statusCode int, description string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]any{
+		"status":         http.StatusText(statusCode),
+		"description":    description,
+		"data":           nil,
+		"custom_message": description,
+	})
+}
+
+func decodeBody(r *http.Request, v any) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return fmt.Errorf("invalid body: %w", err)
+	}
+	return nil
+}
+
+func newID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	id := hex.EncodeToString(b[:])
+	return id[:8] + "-" + id[8:12] + "-" + id[12:16] + "-" + id[16:20] + "-" + id[20:]
+}
@@ -0,0 +1,241 @@
+package ucodetest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ucodesdk "github.com/ucode-io/ucode_sdk"
+	"github.com/ucode-io/ucode_sdk/query"
+)
+
+func TestItems(t *testing.T) {
+	server := New(t)
+	sdk := server.SDK()
+
+	created, _, err := sdk.Items("houses").Create(map[string]any{"name": "house", "price": 15000}).Exec()
+	require.NoError(t, err)
+	guid := cast.ToString(created.Data.Data.Data["guid"])
+	assert.NotEmpty(t, guid)
+
+	server.Seed("houses",
+		map[string]any{"name": "villa", "price": 50000},
+		map[string]any{"name": "flat", "price": 9000},
+	)
+
+	single, _, err := sdk.Items("houses").GetSingle(guid).Exec()
+	require.NoError(t, err)
+	assert.Equal(t, "house", single.Data.Data.Response["name"])
+
+	_, _, err = sdk.Items("houses").GetSingle("missing").Exec()
+	assert.ErrorIs(t, err, ucodesdk.ErrNotFound)
+
+	updated, _, err := sdk.Items("houses").Update(map[string]any{"guid": guid, "price": 16000}).ExecSingle()
+	require.NoError(t, err)
+	assert.Equal(t, float64(16000), updated.Data.Data["price"])
+
+	list, _, err := sdk.Items("houses").GetList().Where(query.Gte("price", 10000)).Sort(map[string]any{"price": -1}).Exec()
+	require.NoError(t, err)
+	if assert.Len(t, list.Data.Data.Response, 2) {
+		assert.Equal(t, "villa", list.Data.Data.Response[0]["name"])
+	}
+
+	list, _, err = sdk.Items("houses").GetList().Search("FLA").Exec()
+	require.NoError(t, err)
+	assert.Len(t, list.Data.Data.Response, 1)
+
+	list, _, err = sdk.Items("houses").GetList().Page(2).Limit(2).Exec()
+	require.NoError(t, err)
+	assert.Len(t, list.Data.Data.Response, 1)
+
+	aggregation, _, err := sdk.Items("houses").GetList().Pipelines(map[string]any{
+		"pipelines": []map[string]any{{"$match": map[string]any{"name": map[string]any{"$in": []string{"villa", "flat"}}}}, {"$limit": 1}},
+	}).ExecAggregation()
+	require.NoError(t, err)
+	assert.Len(t, aggregation.Data.Data.Data, 1)
+
+	for _, stage := range []map[string]any{{"$skip": -1}, {"$limit": -1}, {"$limit": "many"}} {
+		_, _, err = sdk.Items("houses").GetList().Pipelines(map[string]any{"pipelines": []map[string]any{stage}}).ExecAggregation()
+		assert.ErrorIs(t, err, ucodesdk.ErrBadRequest, stage)
+	}
+
+	objects := server.Objects("houses")
+	multiple, _, err := sdk.Items("houses").Update(map[string]any{"objects": []map[string]any{
+		{"guid": objects[1]["guid"], "price": 1},
+		{"guid": objects[2]["guid"], "price": 2},
+	}}).ExecMultiple(false)
+	require.NoError(t, err)
+	assert.Len(t, multiple.Data.Data.Objects, 2)
+
+	_, err = sdk.Items("houses").Delete().Single(guid).Exec()
+	require.NoError(t, err)
+	_, err = sdk.Items("houses").Delete().Multiple([]string{cast.ToString(objects[1]["guid"])}).Exec()
+	require.NoError(t, err)
+	assert.Len(t, server.Objects("houses"), 1)
+
+	_, err = sdk.Items("houses").Delete().Single(guid).Exec()
+	assert.ErrorIs(t, err, ucodesdk.ErrNotFound)
+}
+
+func TestUnauthorized(t *testing.T) {
+	server := New(t)
+
+	config := server.Config()
+	config.AppId = "wrong"
+
+	_, _, err := ucodesdk.New(config).Items("houses").GetList().Exec()
+	assert.ErrorIs(t, err, ucodesdk.ErrUnauthorized)
+}
+
+func TestFilesAndFunctions(t *testing.T) {
+	server := New(t)
+	sdk := server.SDK()
+
+	path := filepath.Join(t.TempDir(), "report.txt")
+	require.NoError(t, os.WriteFile(path, []byte("report"), 0o600))
+
+	file, _, err := sdk.Files().Upload(path).Exec()
+	require.NoError(t, err)
+	assert.Equal(t, "report.txt", file.Data.Title)
+	if assert.Len(t, server.Files(), 1) {
+		assert.Equal(t, []byte("report"), server.Files()[0].Content)
+	}
+
+	_, err = sdk.Files().Delete(file.Data.ID).Exec()
+	require.NoError(t, err)
+	assert.Empty(t, server.Files())
+
+	echo, _, err := sdk.Function("echo").Invoke(map[string]any{"a": 1}).Exec()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"a": float64(1)}, echo.Data)
+
+	server.HandleFunction("fail", func(map[string]any) (any, error) { return nil, errors.New("boom") })
+	_, _, err = sdk.Function("fail").Invoke(nil).ExecContext(context.Background())
+	assert.ErrorIs(t, err, ucodesdk.ErrServer)
+}
+
+func TestAuth(t *testing.T) {
+	server := New(t)
+	sdk := server.SDK()
+
+	registered, _, err := sdk.Auth().Register(map[string]any{"data": map[string]any{"login": "john", "password": "secret", "phone": "+998900000000"}}).Exec()
+	require.NoError(t, err)
+	assert.NotEmpty(t, registered.Data.UserId)
+
+	login, _, err := sdk.Auth().Login(map[string]any{"username": "john", "password": "secret"}).Exec()
+	require.NoError(t, err)
+	assert.Equal(t, registered.Data.UserId, login.Data.UserId)
+	assert.NotEmpty(t, login.Data.Token.AccessToken)
+
+	refreshed, _, err := sdk.Auth().RefreshToken(login.Data.Token.RefreshToken).Exec()
+	require.NoError(t, err)
+	assert.NotEqual(t, login.Data.Token.AccessToken, refreshed.Data.Token.AccessToken)
+	_, _, err = sdk.Auth().RefreshToken(login.Data.Token.RefreshToken).Exec()
+	assert.ErrorIs(t, err, ucodesdk.ErrUnauthorized)
+
+	_, _, err = sdk.Auth().Login(map[string]any{"username": "john", "password": "wrong"}).Exec()
+	assert.ErrorIs(t, err, ucodesdk.ErrUnauthorized)
+
+	code, _, err := sdk.Auth().SendCode(map[string]any{"recipient": "+998900000000", "type": "PHONE"}).Exec()
+	require.NoError(t, err)
+	assert.True(t, code.Data.UserFound)
+
+	otp, _, err := sdk.Auth().Login(map[string]any{
+		"login_strategy": "PHONE_OTP",
+		"data":           map[string]any{"phone": "+998900000000", "sms_id": code.Data.SmsId, "otp": DefaultOTP},
+	}).ExecWithOption()
+	require.NoError(t, err)
+	assert.Equal(t, registered.Data.UserId, otp.Data.UserId)
+
+	_, err = sdk.Auth().ResetPassword(map[string]any{"user_id": registered.Data.UserId, "password": "new"}).Exec()
+	require.NoError(t, err)
+	_, _, err = sdk.Auth().Login(map[string]any{"username": "john", "password": "new"}).Exec()
+	assert.NoError(t, err)
+}
+
+func TestSessions(t *testing.T) {
+	server := New(t)
+	sdk := server.SDK()
+	userId := server.AddUser("john", "secret", map[string]any{"phone": "+998900000000"})
+
+	first, _, err := sdk.Auth().Login(map[string]any{"username": "john", "password": "secret"}).Exec()
+	require.NoError(t, err)
+	second, _, err := sdk.Auth().Login(map[string]any{"username": "john", "password": "secret"}).Exec()
+	require.NoError(t, err)
+
+	sessions, _, err := sdk.Auth().Sessions(ucodesdk.ListSessionsRequest{UserId: userId}).Exec()
+	require.NoError(t, err)
+	require.Equal(t, 2, sessions.Data.Count)
+	require.Len(t, sessions.Data.Sessions, 2)
+
+	page, _, err := sdk.Auth().Sessions(ucodesdk.ListSessionsRequest{UserId: userId, Limit: 1, Offset: 1}).Exec()
+	require.NoError(t, err)
+	require.Len(t, page.Data.Sessions, 1)
+	assert.Equal(t, sessions.Data.Sessions[1].Id, page.Data.Sessions[0].Id)
+
+	_, err = sdk.Auth().Logout(ucodesdk.LogoutRequest{RefreshToken: first.Data.Token.RefreshToken}).Exec()
+	require.NoError(t, err)
+	_, _, err = ucodesdk.AsUser(sdk, first).Items("houses").GetList().Exec()
+	assert.ErrorIs(t, err, ucodesdk.ErrUnauthorized)
+
+	sessions, _, err = sdk.Auth().Sessions(ucodesdk.ListSessionsRequest{UserId: userId}).Exec()
+	require.NoError(t, err)
+	require.Len(t, sessions.Data.Sessions, 1)
+
+	_, err = sdk.Auth().DeleteSession(sessions.Data.Sessions[0].Id).Exec()
+	require.NoError(t, err)
+	_, _, err = ucodesdk.AsUser(sdk, second).Items("houses").GetList().Exec()
+	assert.ErrorIs(t, err, ucodesdk.ErrUnauthorized)
+
+	_, err = sdk.Auth().DeleteSession(sessions.Data.Sessions[0].Id).Exec()
+	assert.ErrorIs(t, err, ucodesdk.ErrNotFound)
+}
+
+func TestChangePasswordAndVerifyOTP(t *testing.T) {
+	server := New(t)
+	sdk := server.SDK()
+	userId := server.AddUser("john", "secret", map[string]any{"phone": "+998900000000"})
+
+	login, _, err := sdk.Auth().Login(map[string]any{"username": "john", "password": "secret"}).Exec()
+	require.NoError(t, err)
+	user := ucodesdk.AsUser(sdk, login)
+
+	_, err = user.Auth().ChangePassword(ucodesdk.ChangePasswordRequest{UserId: userId, OldPassword: "wrong", NewPassword: "new"}).Exec()
+	assert.ErrorIs(t, err, ucodesdk.ErrBadRequest)
+	_, err = user.Auth().ChangePassword(ucodesdk.ChangePasswordRequest{UserId: userId, OldPassword: "secret", NewPassword: "new"}).Exec()
+	require.NoError(t, err)
+	_, _, err = sdk.Auth().Login(map[string]any{"username": "john", "password": "new"}).Exec()
+	assert.NoError(t, err)
+
+	_, err = user.Auth().ChangePassword(ucodesdk.ChangePasswordRequest{UserId: userId}).Exec()
+	assert.Error(t, err)
+	_, err = user.Auth().ChangePassword(ucodesdk.ChangePasswordRequest{UserId: userId, NewPassword: "newer"}).Exec()
+	assert.Error(t, err)
+
+	code, _, err := sdk.Auth().SendCode(map[string]any{"recipient": "+998900000000", "type": "PHONE"}).Exec()
+	require.NoError(t, err)
+
+	_, response, err := sdk.Auth().VerifyOTP(ucodesdk.VerifyOTPRequest{SmsId: code.Data.SmsId, Otp: "000000"}).Exec()
+	assert.ErrorIs(t, err, ucodesdk.ErrUnauthorized)
+	assert.NotContains(t, err.Error(), "000000")
+	assert.NotContains(t, response.Data["error"], "000000")
+
+	unreachable := ucodesdk.New(&ucodesdk.Config{BaseAuthUrl: "http://127.0.0.1:1"})
+	_, _, err = unreachable.Auth().VerifyOTP(ucodesdk.VerifyOTPRequest{SmsId: "sms-1", Otp: "424242"}).Exec()
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "424242")
+
+	verified, _, err := sdk.Auth().VerifyOTP(ucodesdk.VerifyOTPRequest{SmsId: code.Data.SmsId, Otp: DefaultOTP}).Exec()
+	require.NoError(t, err)
+	assert.True(t, verified.Data.UserFound)
+	assert.Equal(t, userId, verified.Data.UserId)
+	assert.NotEmpty(t, verified.Data.Token.AccessToken)
+
+	_, _, err = sdk.Auth().VerifyOTP(ucodesdk.VerifyOTPRequest{}).Exec()
+	assert.Error(t, err)
+}
@@ -0,0 +1,34 @@
+package ucodesdk
+
+// AsUser returns a client acting on behalf of the user of login: its calls
+// carry the user's access token, refreshed before it expires, and the
+// Resource-Id and Environment-Id of the login, so the platform applies the
+// user's role and row-level permissions instead of app-level privileges.
+//
+//	login, _, err := sdk.Auth().Login(credentials).Exec()
+//	user := ucodesdk.AsUser(sdk, login)
+//	orders, _, err := user.Items("orders").GetList().Exec() // only the orders the user may see
+func AsUser(sdk UcodeApis, login LoginResponse) UcodeApis {
+	return asUser(sdk, RefreshingTokenSource(sdk, login.Data.Token), login.Data.ResourceId, login.Data.EnvironmentId)
+}
+
+// WithAccessToken returns a client acting with accessToken, e.g. the token
+// of the user who called a function, on the given resource and environment.
+// The token is used as is and never refreshed.
+func WithAccessToken(sdk UcodeApis, accessToken, resourceId, environmentId string) UcodeApis {
+	return asUser(sdk, StaticTokenSource(&Token{AccessToken: accessToken}), resourceId, environmentId)
+}
+
+func asUser(sdk UcodeApis, ts TokenSource, resourceId, environmentId string) UcodeApis {
+	user := WithTokenSource(sdk, ts)
+
+	cfg := user.Config()
+	if resourceId != "" {
+		cfg.ResourceId = resourceId
+	}
+	if environmentId != "" {
+		cfg.EnvironmentId = environmentId
+	}
+
+	return user
+}
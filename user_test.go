@@ -0,0 +1,61 @@
+package ucodesdk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsUser(t *testing.T) {
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	sdk := New(&Config{BaseURL: server.URL, AppId: "app"})
+
+	var login LoginResponse
+	login.Data.Token = &Token{AccessToken: "user-token", ExpiresAt: time.Now().Add(time.Hour).UTC().Format(time.RFC3339)}
+	login.Data.ResourceId = "resource-1"
+	login.Data.EnvironmentId = "env-1"
+
+	user := AsUser(sdk, login)
+
+	_, _, err := user.Items("orders").GetList().Exec()
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer user-token", headers.Get("Authorization"))
+	assert.Equal(t, "resource-1", headers.Get("Resource-Id"))
+	assert.Equal(t, "env-1", headers.Get("Environment-Id"))
+	assert.Empty(t, headers.Get("X-API-KEY"))
+
+	_, _, err = sdk.Items("orders").GetList().Exec()
+	require.NoError(t, err)
+	assert.Equal(t, "app", headers.Get("X-API-KEY"))
+	assert.Empty(t, headers.Get("Resource-Id"))
+
+	_, _, err = WithAccessToken(sdk, "raw-token", "resource-2", "env-2").Function("notify").Invoke(nil).Exec()
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer raw-token", headers.Get("Authorization"))
+	assert.Equal(t, "resource-2", headers.Get("Resource-Id"))
+}
+
+func TestRefreshingTokenSource(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := RefreshingTokenSource(New(&Config{}), nil).Token(ctx)
+	assert.ErrorIs(t, err, ErrNoToken)
+
+	expired := &Token{AccessToken: "old", ExpiresAt: time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)}
+	_, err = RefreshingTokenSource(New(&Config{}), expired).Token(ctx)
+	assert.ErrorIs(t, err, ErrTokenExpired)
+
+	_, err = StaticTokenSource(nil).Token(ctx)
+	assert.ErrorIs(t, err, ErrNoToken)
+}
@@ -0,0 +1,69 @@
+package ucodesdk
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ucode-io/ucode_sdk/query"
+)
+
+func TestGetListWhere(t *testing.T) {
+	var data string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data = r.URL.Query().Get("data")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	ucodeApi := New(&Config{BaseURL: server.URL})
+
+	_, _, err := ucodeApi.Items("orders").
+		GetList().
+		Limit(10).
+		Where(
+			query.Eq("status", "paid & shipped"),
+			query.Between("amount", 100, 500),
+			query.Or(query.In("client_id", "a", "b"), query.Contains(query.Path("client_id_data", "name"), "#1")),
+		).
+		Exec()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		`{"$or":[{"client_id":{"$in":["a","b"]}},{"client_id_data.name":{"$regex":"#1"}}],"amount":{"$gte":100,"$lte":500},"limit":10,"offset":0,"status":"paid \u0026 shipped"}`,
+		data,
+	)
+}
+
+func TestGetListWhereChained(t *testing.T) {
+	var data string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data = r.URL.Query().Get("data")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	ucodeApi := New(&Config{BaseURL: server.URL})
+
+	_, _, err := ucodeApi.Items("orders").
+		GetList().
+		Limit(10).
+		Where(query.Gte("price", 1)).
+		Where(query.Lte("price", 9)).
+		Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"limit":10,"offset":0,"price":{"$gte":1,"$lte":9}}`, data)
+
+	_, _, err = ucodeApi.Items("orders").
+		GetList().
+		Limit(10).
+		Where(query.Or(query.Eq("status", "paid"), query.Eq("status", "shipped"))).
+		Where(query.Or(query.Gt("amount", 100), query.Eq("vip", true))).
+		Where(query.Eq("status", "archived")).
+		Exec()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		`{"$and":[{"$or":[{"amount":{"$gt":100}},{"vip":true}]}],"$or":[{"status":"paid"},{"status":"shipped"}],"limit":10,"offset":0,"status":"archived"}`,
+		data,
+	)
+}